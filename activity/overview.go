@@ -0,0 +1,106 @@
+package activity
+
+import (
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/table"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/pidanou/helm-tui/components"
+	"github.com/pidanou/helm-tui/helm"
+)
+
+var activityCols = []components.ColumnDefinition{
+	{Title: "Started", Width: 10},
+	{Title: "Duration", Width: 10},
+	{Title: "Exit", Width: 6},
+	{Title: "Command", FlexFactor: 1},
+}
+
+// Model lists the helm commands issued during the session, most recent
+// first.
+type Model struct {
+	log           *helm.ActivityLog
+	entries       []helm.Activity
+	seen          int
+	activityTable table.Model
+	detailsVP     viewport.Model
+	showDetails   bool
+	help          help.Model
+	keys          keyMap
+	width         int
+	height        int
+}
+
+func InitModel(client helm.HelmClient) Model {
+	t := components.GenerateTable()
+	t.Focus()
+	return Model{log: client.Activity(), activityTable: t, help: help.New(), keys: overviewKeys}
+}
+
+func (m Model) Init() tea.Cmd {
+	return nil
+}
+
+func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		components.SetTable(&m.activityTable, activityCols, m.width)
+		m.detailsVP = viewport.New(m.width-6, m.height-5) // -5: 2*1 padding, 2 borders and helper
+		m.detailsVP.SetContent(m.details())
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, m.keys.Details):
+			if !m.showDetails && m.activityTable.SelectedRow() != nil {
+				m.showDetails = true
+				m.detailsVP.SetContent(m.details())
+				m.detailsVP.GotoTop()
+				return m, nil
+			}
+		case key.Matches(msg, m.keys.Back):
+			m.showDetails = false
+			return m, nil
+		}
+		if m.showDetails {
+			m.detailsVP, cmd = m.detailsVP.Update(msg)
+			return m, cmd
+		}
+		m.activityTable, cmd = m.activityTable.Update(msg)
+	}
+	// commands report back through messages: look for new activities on
+	// every update, once the columns are known
+	if m.log.Total() != m.seen && len(m.activityTable.Columns()) > 0 {
+		m.refresh()
+	}
+	return m, cmd
+}
+
+// refresh reloads the table from the log, keeping the cursor on the same
+// activity.
+func (m *Model) refresh() {
+	added := m.log.Total() - m.seen
+	m.seen = m.log.Total()
+	m.entries = m.log.Entries()
+	rows := make([]table.Row, 0, len(m.entries))
+	for i := len(m.entries) - 1; i >= 0; i-- {
+		rows = append(rows, activityRow(m.entries[i]))
+	}
+	cursor := m.activityTable.Cursor()
+	m.activityTable.SetRows(rows)
+	if cursor > 0 {
+		m.activityTable.SetCursor(min(cursor+added, len(rows)-1))
+	}
+}
+
+// selected returns the activity under the cursor.
+func (m Model) selected() (helm.Activity, bool) {
+	i := len(m.entries) - 1 - m.activityTable.Cursor()
+	if i < 0 || i >= len(m.entries) {
+		return helm.Activity{}, false
+	}
+	return m.entries[i], true
+}
@@ -0,0 +1,30 @@
+package activity
+
+import (
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/pidanou/helm-tui/helpers"
+)
+
+type keyMap struct {
+	Details key.Binding
+	Back    key.Binding
+}
+
+var overviewKeys = keyMap{
+	Details: key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "Details")),
+	Back:    key.NewBinding(key.WithKeys("esc"), key.WithHelp("esc", "Back")),
+}
+
+func init() {
+	helpers.RegisterKeys("activity", map[string]*key.Binding{"details": &overviewKeys.Details, "back": &overviewKeys.Back}, "global", "table")
+}
+
+func (k keyMap) ShortHelp() []key.Binding {
+	return []key.Binding{k.Details, k.Back}
+}
+
+// FullHelp returns keybindings for the expanded help view. It's part of the
+// key.Map interface.
+func (k keyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{}
+}
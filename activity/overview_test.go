@@ -0,0 +1,30 @@
+package activity
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/pidanou/helm-tui/helm"
+	"github.com/pidanou/helm-tui/types"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestListActivity verifies that issued commands are listed most recent first and can be inspected.
+func TestListActivity(t *testing.T) {
+	client := helm.NewFakeClient()
+	m := InitModel(client)
+	updated, _ := m.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+
+	_, _ = client.ListReleases("")
+	_ = client.Uninstall("web", "default")
+	updated, _ = updated.Update(types.DeleteMsg{})
+
+	rows := updated.(Model).activityTable.Rows()
+	assert.Len(t, rows, 2)
+	assert.Equal(t, "Uninstall web default", rows[0][3])
+	assert.Equal(t, "ListReleases", rows[1][3])
+
+	updated, _ = updated.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	assert.True(t, updated.(Model).showDetails)
+	assert.Contains(t, updated.(Model).detailsVP.View(), "Command:   Uninstall web default")
+}
@@ -0,0 +1,51 @@
+package activity
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/table"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/pidanou/helm-tui/helm"
+	"github.com/pidanou/helm-tui/helpers"
+	"github.com/pidanou/helm-tui/styles"
+)
+
+func activityRow(a helm.Activity) table.Row {
+	return table.Row{a.Start.Format(time.TimeOnly), a.Duration.Round(time.Millisecond).String(), strconv.Itoa(a.ExitCode), a.CommandLine()}
+}
+
+func (m Model) details() string {
+	a, ok := m.selected()
+	if !ok {
+		return ""
+	}
+	var out strings.Builder
+	fmt.Fprintf(&out, "Command:   %s\n", a.CommandLine())
+	fmt.Fprintf(&out, "Started:   %s\n", a.Start.Format(time.RFC3339))
+	fmt.Fprintf(&out, "Duration:  %s\n", a.Duration)
+	fmt.Fprintf(&out, "Exit code: %d\n", a.ExitCode)
+	if a.Stdout != "" {
+		fmt.Fprintf(&out, "\nSTDOUT:\n%s\n", a.Stdout)
+	}
+	if a.Stderr != "" {
+		fmt.Fprintf(&out, "\nSTDERR:\n%s\n", a.Stderr)
+	}
+	return out.String()
+}
+
+func (m Model) View() string {
+	helperStyle := m.help.Styles.ShortSeparator
+	helpView := m.help.View(m.keys) + helperStyle.Render(" • ") + m.help.View(helpers.CommonKeys)
+	if m.showDetails {
+		topBorder := styles.GenerateTopBorderWithTitle(" Activity ", m.detailsVP.Width+4, styles.Border, styles.InactiveStyle)
+		view := styles.InactiveStyle.Padding(1, 2).Border(styles.Border, false, true, true).Render(m.detailsVP.View())
+		return lipgloss.JoinVertical(lipgloss.Left, topBorder, view, helpView)
+	}
+	m.activityTable.SetHeight(m.height - 3)
+	topBorder := styles.GenerateTopBorderWithTitle(" Activity ", m.activityTable.Width(), styles.Border, styles.InactiveStyle)
+	view := styles.InactiveStyle.Border(styles.Border, false, true, true).Render(m.activityTable.View())
+	return lipgloss.JoinVertical(lipgloss.Left, topBorder, view, helpView)
+}
@@ -0,0 +1,147 @@
+package components
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/viewport"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/pidanou/helm-tui/helpers"
+	"github.com/pidanou/helm-tui/styles"
+	"sigs.k8s.io/yaml"
+)
+
+type manifestKeyMap struct {
+	Next     key.Binding
+	Previous key.Binding
+}
+
+// ShortHelp returns keybindings to be shown in the mini help view. It's part
+// of the key.Map interface.
+func (k manifestKeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{k.Next, k.Previous}
+}
+
+// FullHelp returns keybindings for the expanded help view. It's part of the
+// key.Map interface.
+func (k manifestKeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{}
+}
+
+// ManifestKeys move between the sections of a rendered manifest.
+var ManifestKeys = manifestKeyMap{
+	Next:     key.NewBinding(key.WithKeys("n"), key.WithHelp("n", "Next kind")),
+	Previous: key.NewBinding(key.WithKeys("N"), key.WithHelp("N", "Previous kind")),
+}
+
+func init() {
+	helpers.RegisterKeys("manifest", map[string]*key.Binding{"nextKind": &ManifestKeys.Next, "previousKind": &ManifestKeys.Previous}, "global")
+}
+
+var documentSeparator = regexp.MustCompile(`(?m)^---\s*$`)
+
+// Resource is a document of a manifest.
+type Resource struct {
+	Kind string
+	Name string
+	Doc  string
+	// Key identifies the resource in its manifest: its kind and name, or its
+	// position when it has no kind.
+	Key string
+}
+
+// SplitManifest splits a manifest into its documents, skipping the empty
+// ones.
+func SplitManifest(manifest string) []Resource {
+	var resources []Resource
+	for i, doc := range documentSeparator.Split(manifest, -1) {
+		doc = strings.Trim(doc, "\n")
+		if strings.TrimSpace(doc) == "" {
+			continue
+		}
+		var meta struct {
+			Kind     string `json:"kind"`
+			Metadata struct {
+				Name string `json:"name"`
+			} `json:"metadata"`
+		}
+		r := Resource{Doc: doc + "\n", Key: fmt.Sprintf("document %d", i+1)}
+		if err := yaml.Unmarshal([]byte(doc), &meta); err == nil && meta.Kind != "" {
+			r.Kind, r.Name = meta.Kind, meta.Metadata.Name
+			r.Key = r.Kind + "/" + r.Name
+		}
+		resources = append(resources, r)
+	}
+	return resources
+}
+
+// RenderManifest renders the resources of manifest grouped by kind, in the
+// order the kinds first appear, followed by notes. It returns the lines the
+// sections start at.
+func RenderManifest(manifest, notes string) (string, []int) {
+	header := lipgloss.NewStyle().Bold(true).Foreground(styles.CurrentTheme.Highlight)
+	var kinds []string
+	byKind := map[string][]Resource{}
+	for _, r := range SplitManifest(manifest) {
+		kind := r.Kind
+		if kind == "" {
+			kind = "Other"
+		}
+		if _, ok := byKind[kind]; !ok {
+			kinds = append(kinds, kind)
+		}
+		byKind[kind] = append(byKind[kind], r)
+	}
+
+	var counts []string
+	total := 0
+	for _, kind := range kinds {
+		counts = append(counts, fmt.Sprintf("%d %s", len(byKind[kind]), kind))
+		total += len(byKind[kind])
+	}
+	summary := fmt.Sprintf("%d resources", total)
+	if total > 0 {
+		summary += ": " + strings.Join(counts, ", ")
+	}
+	lines := []string{summary}
+	var sections []int
+	for _, kind := range kinds {
+		sections = append(sections, len(lines)+1)
+		lines = append(lines, "", header.Render(fmt.Sprintf("━━ %s (%d)", kind, len(byKind[kind]))))
+		for i, r := range byKind[kind] {
+			if i > 0 {
+				lines = append(lines, "---")
+			}
+			lines = append(lines, strings.Split(strings.TrimSuffix(r.Doc, "\n"), "\n")...)
+		}
+	}
+	if notes = strings.Trim(notes, "\n"); notes != "" {
+		sections = append(sections, len(lines)+1)
+		lines = append(lines, "", header.Render("━━ NOTES"))
+		lines = append(lines, strings.Split(notes, "\n")...)
+	}
+	return strings.Join(lines, "\n"), sections
+}
+
+// JumpTo scrolls vp to the next of lines, or the previous one when forward
+// is false.
+func JumpTo(vp *viewport.Model, lines []int, forward bool) {
+	offset := vp.YOffset
+	if forward {
+		for _, line := range lines {
+			if line > offset {
+				vp.SetYOffset(line)
+				return
+			}
+		}
+		return
+	}
+	for i := len(lines) - 1; i >= 0; i-- {
+		if lines[i] < offset {
+			vp.SetYOffset(lines[i])
+			return
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package components
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/bubbles/viewport"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestSplitManifest verifies that the documents of a manifest are keyed by
+// kind and name, or by position when they have no kind.
+func TestSplitManifest(t *testing.T) {
+	manifest := "---\n# Source: web/templates/service.yaml\nkind: Service\nmetadata:\n  name: web\n---\nfoo: bar\n---\n"
+
+	resources := SplitManifest(manifest)
+
+	assert.Len(t, resources, 2)
+	assert.Equal(t, "Service", resources[0].Kind)
+	assert.Equal(t, "Service/web", resources[0].Key)
+	assert.Equal(t, "document 3", resources[1].Key)
+}
+
+// TestRenderManifest verifies that the resources are grouped by kind, in the
+// order kinds first appear, and followed by the notes.
+func TestRenderManifest(t *testing.T) {
+	manifest := "kind: Service\nmetadata:\n  name: web\n---\nkind: Deployment\nmetadata:\n  name: web\n---\nkind: Service\nmetadata:\n  name: web-headless\n"
+
+	content, sections := RenderManifest(manifest, "Visit http://web\n")
+	lines := strings.Split(content, "\n")
+
+	assert.Equal(t, "3 resources: 2 Service, 1 Deployment", lines[0])
+	assert.Len(t, sections, 3)
+	assert.Contains(t, lines[sections[0]], "━━ Service (2)")
+	assert.Contains(t, lines[sections[1]], "━━ Deployment (1)")
+	assert.Contains(t, lines[sections[2]], "━━ NOTES")
+	assert.Equal(t, "Visit http://web", lines[len(lines)-1])
+	assert.Less(t, strings.Index(content, "web-headless"), strings.Index(content, "Deployment (1)"))
+}
+
+// TestJumpTo verifies that the viewport scrolls to the next and previous
+// lines.
+func TestJumpTo(t *testing.T) {
+	vp := viewport.New(10, 2)
+	vp.SetContent(strings.Repeat("line\n", 20))
+
+	JumpTo(&vp, []int{5, 10}, true)
+	assert.Equal(t, 5, vp.YOffset)
+	JumpTo(&vp, []int{5, 10}, true)
+	assert.Equal(t, 10, vp.YOffset)
+	JumpTo(&vp, []int{5, 10}, false)
+	assert.Equal(t, 5, vp.YOffset)
+}
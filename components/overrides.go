@@ -0,0 +1,237 @@
+package components
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/pidanou/helm-tui/helm"
+	"github.com/pidanou/helm-tui/helpers"
+	"github.com/pidanou/helm-tui/styles"
+	"sigs.k8s.io/yaml"
+)
+
+// OverrideKind tells which flag passes an override to helm.
+type OverrideKind int
+
+const (
+	OverrideSet OverrideKind = iota
+	OverrideString
+	OverrideJSON
+)
+
+var overrideFlags = []string{"--set", "--set-string", "--set-json"}
+
+// Flag is the helm flag taking overrides of kind k.
+func (k OverrideKind) Flag() string {
+	return overrideFlags[k]
+}
+
+// Override is a key=value override of the values of a chart.
+type Override struct {
+	Kind OverrideKind
+	// Entry is the key=value given to the flag.
+	Entry string
+}
+
+// Key is the path of the value Entry overrides.
+func (o Override) Key() string {
+	k, _, _ := strings.Cut(o.Entry, "=")
+	return k
+}
+
+type overridesKeyMap struct {
+	Up     key.Binding
+	Down   key.Binding
+	Kind   key.Binding
+	Edit   key.Binding
+	Remove key.Binding
+}
+
+// ShortHelp returns keybindings to be shown in the mini help view. It's part
+// of the key.Map interface.
+func (k overridesKeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{k.Up, k.Down, k.Kind, k.Edit, k.Remove}
+}
+
+// FullHelp returns keybindings for the expanded help view. It's part of the
+// key.Map interface.
+func (k overridesKeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{}
+}
+
+// OverridesKeys edit the overrides listed by Overrides, Up and Down
+// selecting one while the input is empty.
+var OverridesKeys = overridesKeyMap{
+	Up:     key.NewBinding(key.WithKeys("up"), key.WithHelp("↑", "Select previous")),
+	Down:   key.NewBinding(key.WithKeys("down"), key.WithHelp("↓", "Select next")),
+	Kind:   key.NewBinding(key.WithKeys("ctrl+t"), key.WithHelp("ctrl+t", "Change type")),
+	Edit:   key.NewBinding(key.WithKeys("ctrl+e"), key.WithHelp("ctrl+e", "Edit selected")),
+	Remove: key.NewBinding(key.WithKeys("ctrl+x"), key.WithHelp("ctrl+x", "Remove selected")),
+}
+
+func init() {
+	helpers.RegisterKeys("overrides", map[string]*key.Binding{"up": &OverridesKeys.Up, "down": &OverridesKeys.Down, "kind": &OverridesKeys.Kind, "edit": &OverridesKeys.Edit, "remove": &OverridesKeys.Remove}, "global")
+}
+
+// Overrides lists the overrides added in a wizard, before the helm command
+// is built.
+type Overrides struct {
+	Items []Override
+	// Kind is the kind of the override being typed.
+	Kind OverrideKind
+	// selected is the index of the selected item, -1 when none is.
+	selected int
+}
+
+// NewOverrides returns an empty list of overrides.
+func NewOverrides() Overrides {
+	return Overrides{selected: -1}
+}
+
+// Add adds entry, replacing the override of the same key, and reports
+// whether entry is a key=value.
+func (o *Overrides) Add(entry string) bool {
+	override := Override{Kind: o.Kind, Entry: entry}
+	if !strings.Contains(entry, "=") || override.Key() == "" {
+		return false
+	}
+	for i, item := range o.Items {
+		if item.Key() == override.Key() {
+			o.Items[i] = override
+			return true
+		}
+	}
+	o.Items = append(o.Items, override)
+	return true
+}
+
+// Update handles msg when it edits the list, input being where overrides are
+// typed, and reports whether it did.
+func (o *Overrides) Update(input *textinput.Model, msg tea.KeyMsg) bool {
+	switch {
+	case key.Matches(msg, OverridesKeys.Kind):
+		o.Kind = (o.Kind + 1) % OverrideKind(len(overrideFlags))
+	case input.Value() != "":
+		return false
+	case key.Matches(msg, OverridesKeys.Up):
+		if len(o.Items) > 0 {
+			o.selected = max(o.selected, 0)
+			o.selected = (o.selected + len(o.Items) - 1) % len(o.Items)
+		}
+	case key.Matches(msg, OverridesKeys.Down):
+		if len(o.Items) > 0 {
+			o.selected = (o.selected + 1) % len(o.Items)
+		}
+	case key.Matches(msg, OverridesKeys.Edit) && o.selected >= 0:
+		item := o.Items[o.selected]
+		o.remove()
+		o.Kind = item.Kind
+		input.SetValue(item.Entry)
+		input.CursorEnd()
+	case key.Matches(msg, OverridesKeys.Remove) && o.selected >= 0:
+		o.remove()
+	default:
+		return false
+	}
+	return true
+}
+
+func (o *Overrides) remove() {
+	o.Items = append(o.Items[:o.selected], o.Items[o.selected+1:]...)
+	o.selected = min(o.selected, len(o.Items)-1)
+}
+
+// Reset empties the list.
+func (o *Overrides) Reset() {
+	*o = NewOverrides()
+}
+
+// Flags returns the entries of the overrides by flag: --set, --set-string
+// and --set-json.
+func (o Overrides) Flags() (set, setString, setJSON []string) {
+	for _, item := range o.Items {
+		switch item.Kind {
+		case OverrideSet:
+			set = append(set, item.Entry)
+		case OverrideString:
+			setString = append(setString, item.Entry)
+		case OverrideJSON:
+			setJSON = append(setJSON, item.Entry)
+		}
+	}
+	return set, setString, setJSON
+}
+
+// View lists the overrides as flags, the selected one highlighted.
+func (o Overrides) View() string {
+	if len(o.Items) == 0 {
+		return ""
+	}
+	selected := lipgloss.NewStyle().Foreground(styles.CurrentTheme.Highlight)
+	lines := make([]string, len(o.Items))
+	for i, item := range o.Items {
+		line := fmt.Sprintf("  %s %s", item.Kind.Flag(), item.Entry)
+		if i == o.selected {
+			line = selected.Render("> " + line[2:])
+		}
+		lines[i] = line
+	}
+	return strings.Join(lines, "\n")
+}
+
+// ValuePathsMsg holds the key paths of the default values of a chart.
+type ValuePathsMsg struct {
+	Paths []string
+}
+
+// FetchValuePaths lists the key paths of the default values of a chart
+// version, no paths being suggested when they cannot be read.
+func FetchValuePaths(client helm.HelmClient, chart, version string) tea.Cmd {
+	return func() tea.Msg {
+		values, err := client.ShowValues(chart, version)
+		if err != nil {
+			helpers.Println("listing the values of", chart+":", err)
+			return ValuePathsMsg{}
+		}
+		return ValuePathsMsg{Paths: ValuePaths(values)}
+	}
+}
+
+// ValuePaths lists the key paths of a values YAML as --set takes them, such
+// as image.tag or ports[0].name, dots in keys being escaped.
+func ValuePaths(values string) []string {
+	var doc map[string]interface{}
+	if err := yaml.Unmarshal([]byte(values), &doc); err != nil {
+		return nil
+	}
+	var paths []string
+	var walk func(prefix string, value interface{})
+	walk = func(prefix string, value interface{}) {
+		if prefix != "" {
+			paths = append(paths, prefix)
+		}
+		switch value := value.(type) {
+		case map[string]interface{}:
+			for k, v := range value {
+				k = strings.ReplaceAll(k, ".", `\.`)
+				if prefix != "" {
+					k = prefix + "." + k
+				}
+				walk(k, v)
+			}
+		case []interface{}:
+			for i, v := range value {
+				walk(prefix+"["+strconv.Itoa(i)+"]", v)
+			}
+		}
+	}
+	walk("", doc)
+	sort.Strings(paths)
+	return paths
+}
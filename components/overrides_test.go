@@ -0,0 +1,63 @@
+package components
+
+import (
+	"testing"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestValuePaths verifies that maps, lists and keys holding dots are listed
+// as --set paths.
+func TestValuePaths(t *testing.T) {
+	values := "image:\n  tag: latest\nports:\n  - name: http\nannotations:\n  example.com/team: web\n"
+
+	assert.Equal(t, []string{"annotations", `annotations.example\.com/team`, "image", "image.tag", "ports", "ports[0]", "ports[0].name"}, ValuePaths(values))
+	assert.Empty(t, ValuePaths("- not a map"))
+}
+
+// TestOverrides verifies that overrides replace those of the same key, and
+// that the selected one can be edited or removed while the input is empty.
+func TestOverrides(t *testing.T) {
+	o := NewOverrides()
+	input := textinput.New()
+
+	assert.False(t, o.Add("replicaCount"))
+	assert.True(t, o.Add("replicaCount=2"))
+	assert.True(t, o.Add("replicaCount=3"))
+	assert.True(t, o.Update(&input, tea.KeyMsg{Type: tea.KeyCtrlT}))
+	assert.True(t, o.Add("image.tag=1.27"))
+	set, setString, setJSON := o.Flags()
+	assert.Equal(t, []string{"replicaCount=3"}, set)
+	assert.Equal(t, []string{"image.tag=1.27"}, setString)
+	assert.Empty(t, setJSON)
+
+	assert.False(t, o.Update(&input, tea.KeyMsg{Type: tea.KeyCtrlE}), "nothing is selected")
+	assert.True(t, o.Update(&input, tea.KeyMsg{Type: tea.KeyUp}))
+	assert.Contains(t, o.View(), "> --set-string image.tag=1.27")
+	assert.True(t, o.Update(&input, tea.KeyMsg{Type: tea.KeyCtrlE}))
+	assert.Equal(t, "image.tag=1.27", input.Value())
+	assert.Equal(t, OverrideString, o.Kind)
+	assert.Len(t, o.Items, 1)
+
+	assert.False(t, o.Update(&input, tea.KeyMsg{Type: tea.KeyDown}), "the input is being edited")
+	input.SetValue("")
+	o.Update(&input, tea.KeyMsg{Type: tea.KeyDown})
+	assert.True(t, o.Update(&input, tea.KeyMsg{Type: tea.KeyCtrlX}))
+	assert.Empty(t, o.Items)
+}
+
+// TestOverridesRemappedKeys verifies that the overrides are selected with
+// the keys bound to up and down.
+func TestOverridesRemappedKeys(t *testing.T) {
+	OverridesKeys.Up.SetKeys("ctrl+k")
+	t.Cleanup(func() { OverridesKeys.Up.SetKeys("up") })
+	o := NewOverrides()
+	input := textinput.New()
+	o.Add("replicaCount=2")
+
+	assert.False(t, o.Update(&input, tea.KeyMsg{Type: tea.KeyUp}))
+	assert.True(t, o.Update(&input, tea.KeyMsg{Type: tea.KeyCtrlK}))
+	assert.Contains(t, o.View(), "> --set replicaCount=2")
+}
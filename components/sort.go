@@ -0,0 +1,153 @@
+package components
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/pidanou/helm-tui/helpers"
+)
+
+// SortKind tells how the values of a column compare.
+type SortKind int
+
+const (
+	SortText SortKind = iota
+	SortNumber
+	// SortTime compares timestamps printed by helm.
+	SortTime
+)
+
+// helmTimeLayout is the layout of the timestamps printed by helm, such as
+// "2024-05-12 10:33:25.371158 +0200 CEST".
+const helmTimeLayout = "2006-01-02 15:04:05.999999999 -0700 MST"
+
+type sortKeyMap struct {
+	Column key.Binding
+	Order  key.Binding
+}
+
+// ShortHelp returns keybindings to be shown in the mini help view. It's part
+// of the key.Map interface.
+func (k sortKeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{k.Column, k.Order}
+}
+
+// FullHelp returns keybindings for the expanded help view. It's part of the
+// key.Map interface.
+func (k sortKeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{}
+}
+
+// SortKeys sort the tables handling SortKey.
+var SortKeys = sortKeyMap{
+	Column: key.NewBinding(key.WithKeys("s"), key.WithHelp("s", "Sort column")),
+	Order:  key.NewBinding(key.WithKeys("S"), key.WithHelp("S", "Reverse sort")),
+}
+
+func init() {
+	helpers.RegisterKeys("table", map[string]*key.Binding{"sortColumn": &SortKeys.Column, "sortOrder": &SortKeys.Order})
+}
+
+// tableSort is the sort order of a table, -1 keeping the listed order.
+type tableSort struct {
+	column int
+	desc   bool
+}
+
+// tableSorts remembers the sort order of the tables for the session, by
+// table name.
+var tableSorts = map[string]tableSort{}
+
+func sortOf(name string) tableSort {
+	s, ok := tableSorts[name]
+	if !ok {
+		return tableSort{column: -1}
+	}
+	return s
+}
+
+// SortKey updates the sort order of the table called name when msg is one of
+// SortKeys, and reports whether it was. The sort column moves to the next
+// visible column, then back to the listed order.
+func SortKey(msg tea.KeyMsg, name string, cols []ColumnDefinition) bool {
+	s := sortOf(name)
+	switch {
+	case key.Matches(msg, SortKeys.Column):
+		s.column++
+		for s.column < len(cols) && cols[s.column].Width == 0 && cols[s.column].FlexFactor == 0 {
+			s.column++
+		}
+		if s.column >= len(cols) {
+			s.column = -1
+		}
+	case key.Matches(msg, SortKeys.Order):
+		s.desc = !s.desc
+	default:
+		return false
+	}
+	tableSorts[name] = s
+	return true
+}
+
+// Sort sorts the rows of t, the table called name, in its remembered order,
+// keeping the cursor on the same row, and marks the sort column in the
+// header.
+func Sort(t *table.Model, name string, cols []ColumnDefinition) {
+	s := sortOf(name)
+	columns := t.Columns()
+	for i := range columns {
+		if i < len(cols) {
+			columns[i].Title = cols[i].Title
+		}
+	}
+	if s.column < 0 || s.column >= len(columns) {
+		t.SetColumns(columns)
+		return
+	}
+	indicator := " ▲"
+	if s.desc {
+		indicator = " ▼"
+	}
+	columns[s.column].Title += indicator
+	t.SetColumns(columns)
+
+	selected := t.SelectedRow()
+	rows := append([]table.Row{}, t.Rows()...)
+	sort.SliceStable(rows, func(i, j int) bool {
+		if s.desc {
+			return less(cols[s.column].Sort, rows[j][s.column], rows[i][s.column])
+		}
+		return less(cols[s.column].Sort, rows[i][s.column], rows[j][s.column])
+	})
+	t.SetRows(rows)
+	for i, row := range rows {
+		if selected != nil && strings.Join(row, "\x00") == strings.Join(selected, "\x00") {
+			t.SetCursor(i)
+		}
+	}
+}
+
+// less compares two values of a column, falling back to text when they do
+// not parse.
+func less(kind SortKind, a, b string) bool {
+	switch kind {
+	case SortNumber:
+		x, errX := strconv.ParseFloat(a, 64)
+		y, errY := strconv.ParseFloat(b, 64)
+		if errX == nil && errY == nil {
+			return x < y
+		}
+	case SortTime:
+		x, errX := time.Parse(helmTimeLayout, a)
+		y, errY := time.Parse(helmTimeLayout, b)
+		if errX == nil && errY == nil {
+			return x.Before(y)
+		}
+	}
+	return strings.ToLower(a) < strings.ToLower(b)
+}
@@ -0,0 +1,83 @@
+package components
+
+import (
+	"testing"
+
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+)
+
+var sortCols = []ColumnDefinition{
+	{Title: "Name", FlexFactor: 1},
+	{Title: "hidden", Width: 0},
+	{Title: "Revision", Width: 10, Sort: SortNumber},
+	{Title: "Updated", Width: 36, Sort: SortTime},
+}
+
+func sortedTable(t *testing.T) table.Model {
+	t.Cleanup(func() { delete(tableSorts, "test") })
+	tbl := GenerateTable()
+	SetTable(&tbl, sortCols, 120)
+	tbl.SetRows([]table.Row{
+		{"b", "", "10", "2024-05-12 10:33:25.371158 +0200 CEST"},
+		{"a", "", "9", "2024-05-12 09:00:00.5 +0000 UTC"},
+		{"c", "", "100", "2023-01-01 00:00:00 +0000 UTC"},
+	})
+	tbl.SetCursor(2)
+	return tbl
+}
+
+func names(tbl table.Model) []string {
+	var names []string
+	for _, row := range tbl.Rows() {
+		names = append(names, row[0])
+	}
+	return names
+}
+
+// TestSort verifies that columns are sorted by their kind, skipping hidden
+// columns, and that the cursor follows the selected row.
+func TestSort(t *testing.T) {
+	tbl := sortedTable(t)
+	column := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("s")}
+	order := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("S")}
+
+	assert.True(t, SortKey(column, "test", sortCols))
+	Sort(&tbl, "test", sortCols)
+	assert.Equal(t, []string{"a", "b", "c"}, names(tbl))
+	assert.Equal(t, "Name ▲", tbl.Columns()[0].Title)
+	assert.Equal(t, "c", tbl.SelectedRow()[0])
+
+	SortKey(column, "test", sortCols)
+	Sort(&tbl, "test", sortCols)
+	assert.Equal(t, []string{"a", "b", "c"}, names(tbl), "revisions compare as numbers")
+	assert.Equal(t, "Name", tbl.Columns()[0].Title)
+	assert.Equal(t, "Revision ▲", tbl.Columns()[2].Title)
+
+	SortKey(column, "test", sortCols)
+	SortKey(order, "test", sortCols)
+	Sort(&tbl, "test", sortCols)
+	assert.Equal(t, []string{"a", "b", "c"}, names(tbl), "updated compares as time, newest first")
+	assert.Equal(t, "Updated ▼", tbl.Columns()[3].Title)
+
+	SortKey(column, "test", sortCols)
+	Sort(&tbl, "test", sortCols)
+	assert.Equal(t, "Updated", tbl.Columns()[3].Title, "back to the listed order")
+
+	assert.False(t, SortKey(tea.KeyMsg{Type: tea.KeyEnter}, "test", sortCols))
+}
+
+// TestSortRemembered verifies that the sort order of a table outlives the
+// table and applies to new rows.
+func TestSortRemembered(t *testing.T) {
+	tbl := sortedTable(t)
+	SortKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("S")}, "test", sortCols)
+	SortKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("s")}, "test", sortCols)
+
+	other := GenerateTable()
+	SetTable(&other, sortCols, 120)
+	other.SetRows(tbl.Rows())
+	Sort(&other, "test", sortCols)
+	assert.Equal(t, []string{"c", "b", "a"}, names(other))
+}
@@ -1,9 +1,15 @@
 package components
 
 import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/table"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/mattn/go-runewidth"
+	"github.com/pidanou/helm-tui/helpers"
 	"github.com/pidanou/helm-tui/styles"
 )
 
@@ -11,6 +17,7 @@ type ColumnDefinition struct {
 	Title      string
 	Width      int
 	FlexFactor int
+	Sort       SortKind
 }
 
 func SetTable(t *table.Model, cols []ColumnDefinition, targetWidth int) tea.Cmd {
@@ -53,29 +60,110 @@ func SetTable(t *table.Model, cols []ColumnDefinition, targetWidth int) tea.Cmd
 	return nil
 }
 
-func GenerateTable() table.Model {
-	t := table.New()
-	s := table.DefaultStyles()
+// TableKeys are the bindings of the tables created by GenerateTable.
+var TableKeys = tableKeys()
+
+func tableKeys() table.KeyMap {
 	k := table.DefaultKeyMap()
 	k.HalfPageUp.Unbind()
 	k.PageDown.Unbind()
 	k.HalfPageDown.Unbind()
-	k.HalfPageDown.Unbind()
 	k.GotoBottom.Unbind()
 	k.GotoTop.Unbind()
+	return k
+}
+
+func init() {
+	helpers.RegisterKeys("table", map[string]*key.Binding{
+		"up": &TableKeys.LineUp, "down": &TableKeys.LineDown, "pageUp": &TableKeys.PageUp,
+	})
+}
+
+// tableStyles are the styles of the tables created by GenerateTable.
+var tableStyles = table.DefaultStyles()
+
+// GenerateTable creates a table in the current theme. Its cells can be
+// styled one by one by rendering it with StyledView.
+func GenerateTable() table.Model {
+	t := table.New()
+	t.SetStyles(generateStyles())
+	t.KeyMap = TableKeys
+	return t
+}
+
+func generateStyles() table.Styles {
+	s := tableStyles
 	s.Header = s.Header.
 		BorderStyle(styles.Border).
-		BorderForeground(lipgloss.Color("240")).
+		BorderForeground(styles.CurrentTheme.Subtle).
 		BorderBottom(true).
 		Bold(true)
-	s.Selected = s.Selected.
-		Foreground(lipgloss.Color("229")).
-		Background(lipgloss.Color("57")).
-		Bold(false)
+	s.Selected = styles.Selected(s.Selected).Bold(false)
+	return s
+}
 
-	t.SetStyles(s)
-	t.KeyMap = k
-	return t
+// CellStyle returns the style of a cell, false keeping the table style. A
+// transform set on the style, such as one prefixing a glyph, applies to the
+// value before it is truncated to the column width.
+type CellStyle func(row table.Row, col int) (lipgloss.Style, bool)
+
+// StyledView renders t like its View method, with the cells restyled by
+// style. The selected row keeps the selection colors and only gets the
+// transforms of style. The table does not support styling rows, so the
+// rendered rows are matched back to t.Rows().
+func StyledView(t table.Model, style CellStyle) string {
+	view := t.View()
+	rows := t.Rows()
+	rendered := make(map[string]int, len(rows))
+	for i, row := range rows {
+		rendered[renderRow(t.Columns(), row, nil)] = i
+	}
+	lines := strings.Split(view, "\n")
+	for i, line := range lines {
+		r, ok := rendered[ansi.Strip(line)]
+		if !ok {
+			continue
+		}
+		if r == t.Cursor() {
+			lines[i] = generateStyles().Selected.Render(renderRow(t.Columns(), rows[r], transformOnly(style)))
+			continue
+		}
+		lines[i] = renderRow(t.Columns(), rows[r], style)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// renderRow renders a row like the table does for unselected rows.
+func renderRow(cols []table.Column, row table.Row, style CellStyle) string {
+	cells := make([]string, 0, len(cols))
+	for i, value := range row {
+		if i >= len(cols) || cols[i].Width <= 0 {
+			continue
+		}
+		cell := lipgloss.NewStyle().Width(cols[i].Width).MaxWidth(cols[i].Width).Inline(true)
+		if style != nil {
+			if custom, ok := style(row, i); ok {
+				if transform := custom.GetTransform(); transform != nil {
+					value = transform(value)
+					custom = custom.UnsetTransform()
+				}
+				cell = custom.Inherit(cell)
+			}
+		}
+		cells = append(cells, tableStyles.Cell.Render(cell.Render(runewidth.Truncate(value, cols[i].Width, "…"))))
+	}
+	return lipgloss.JoinHorizontal(lipgloss.Top, cells...)
+}
+
+// transformOnly keeps the transforms of style, dropping its colors.
+func transformOnly(style CellStyle) CellStyle {
+	return func(row table.Row, col int) (lipgloss.Style, bool) {
+		custom, ok := style(row, col)
+		if !ok || custom.GetTransform() == nil {
+			return lipgloss.Style{}, false
+		}
+		return lipgloss.NewStyle().Transform(custom.GetTransform()), true
+	}
 }
 
 func RenderTable(t table.Model, height int, width int) string {
@@ -0,0 +1,34 @@
+package components
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestStyledView verifies that cell transforms apply to every row, the
+// selected one included, before the values are truncated.
+func TestStyledView(t *testing.T) {
+	tbl := GenerateTable()
+	tbl.SetColumns([]table.Column{{Title: "Name", Width: 6}, {Title: "Status", Width: 8}})
+	tbl.SetRows([]table.Row{{"web", "deployed"}, {"db", "failed"}})
+	tbl.SetHeight(4)
+	tbl.Focus()
+	tbl, _ = tbl.Update(tea.KeyMsg{Type: tea.KeyDown})
+
+	glyph := func(row table.Row, col int) (lipgloss.Style, bool) {
+		if col != 1 {
+			return lipgloss.Style{}, false
+		}
+		return lipgloss.NewStyle().Transform(func(s string) string { return "* " + s }), true
+	}
+	view := ansi.Strip(StyledView(tbl, glyph))
+	assert.Contains(t, view, "* deplo…")
+	assert.Contains(t, view, "* failed", "The selected row should be transformed too")
+	assert.NotContains(t, strings.ReplaceAll(view, "* ", ""), "deployed")
+}
@@ -0,0 +1,111 @@
+package components
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/pidanou/helm-tui/helm"
+	"github.com/pidanou/helm-tui/helpers"
+	"github.com/pidanou/helm-tui/styles"
+)
+
+// InvalidValues explains why edited values were refused.
+func InvalidValues(err *helpers.YAMLError) string {
+	return lipgloss.NewStyle().Foreground(styles.CurrentTheme.Error).Render("Invalid values, " + err.Error())
+}
+
+// InvalidValuesKeys relabels the bindings editing the refused values again
+// or aborting.
+func InvalidValuesKeys(err *helpers.YAMLError, edit, abort key.Binding) (key.Binding, key.Binding) {
+	edit.SetHelp(edit.Help().Key, fmt.Sprintf("Edit at line %d", err.Line))
+	abort.SetHelp(abort.Help().Key, "Abort")
+	return edit, abort
+}
+
+// RenderViolations lists the values breaking the schema of a chart, one per
+// line with its path and the line setting it.
+func RenderViolations(violations []helm.Violation) string {
+	path := lipgloss.NewStyle().Foreground(styles.CurrentTheme.Highlight)
+	subtle := lipgloss.NewStyle().Foreground(styles.CurrentTheme.Subtle)
+	width := 0
+	for _, v := range violations {
+		width = max(width, len(v.Path))
+	}
+	lines := []string{fmt.Sprintf("%d values break the schema of the chart", len(violations)), ""}
+	for _, v := range violations {
+		line := ""
+		if v.Line > 0 {
+			line = fmt.Sprintf("line %d", v.Line)
+		}
+		lines = append(lines, fmt.Sprintf("%s  %s  %s", path.Render(v.Path+strings.Repeat(" ", width-len(v.Path))), subtle.Render(fmt.Sprintf("%-9s", line)), v.Message))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// FirstViolationLine is the first line of the values file setting one of
+// violations, 1 when none does.
+func FirstViolationLine(violations []helm.Violation) int {
+	first := 0
+	for _, v := range violations {
+		if v.Line > 0 && (first == 0 || v.Line < first) {
+			first = v.Line
+		}
+	}
+	return max(first, 1)
+}
+
+// SchemaMsg holds the edited values breaking the schema of the chart.
+type SchemaMsg struct {
+	Violations []helm.Violation
+	Err        error
+}
+
+// CheckValues validates the values given to helm, the edited ones included,
+// against the schema of the chart.
+func CheckValues(client helm.HelmClient, chart, version string, sources helm.ValueSources) tea.Cmd {
+	return func() tea.Msg {
+		violations, err := helm.CheckValues(client, chart, version, sources)
+		return SchemaMsg{Violations: violations, Err: err}
+	}
+}
+
+// ViolationsViewport lists violations in a viewport filling a wizard of
+// width and height.
+func ViolationsViewport(width, height int, violations []helm.Violation) viewport.Model {
+	vp := viewport.New(width-2, height-3) // borders and help
+	vp.SetContent(RenderViolations(violations))
+	return vp
+}
+
+type violationsKeyMap struct {
+	Edit key.Binding
+	Back key.Binding
+}
+
+// ShortHelp returns keybindings to be shown in the mini help view. It's part
+// of the key.Map interface.
+func (k violationsKeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{k.Edit, k.Back}
+}
+
+// FullHelp returns keybindings for the expanded help view. It's part of the
+// key.Map interface.
+func (k violationsKeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{}
+}
+
+// ViolationsView shows the values breaking the schema of chart, with the
+// bindings editing them again or going back to the wizard.
+func ViolationsView(vp viewport.Model, h help.Model, chart string, edit, back key.Binding) string {
+	keys := violationsKeyMap{Edit: edit, Back: back}
+	keys.Edit.SetHelp(edit.Help().Key, "Edit values")
+	keys.Back.SetHelp(back.Help().Key, "Back")
+	topBorder := styles.GenerateTopBorderWithTitle(" Values breaking the schema of "+chart+" ", vp.Width, styles.Border, styles.ActiveStyle.Foreground(styles.CurrentTheme.Error))
+	baseStyle := styles.ActiveStyle.Border(styles.Border, false, true, true)
+	return lipgloss.JoinVertical(lipgloss.Top, topBorder, baseStyle.Render(vp.View()), h.View(keys))
+}
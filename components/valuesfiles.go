@@ -0,0 +1,214 @@
+package components
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/pidanou/helm-tui/helpers"
+	"github.com/pidanou/helm-tui/styles"
+)
+
+type valuesFilesKeyMap struct {
+	Up       key.Binding
+	Down     key.Binding
+	MoveUp   key.Binding
+	MoveDown key.Binding
+	Remove   key.Binding
+}
+
+// ShortHelp returns keybindings to be shown in the mini help view. It's part
+// of the key.Map interface.
+func (k valuesFilesKeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{k.Up, k.Down, k.MoveUp, k.MoveDown, k.Remove}
+}
+
+// FullHelp returns keybindings for the expanded help view. It's part of the
+// key.Map interface.
+func (k valuesFilesKeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{}
+}
+
+// ValuesFilesKeys reorder the files listed by ValuesFiles, Up and Down
+// selecting one while the input is empty.
+var ValuesFilesKeys = valuesFilesKeyMap{
+	Up:       key.NewBinding(key.WithKeys("up"), key.WithHelp("↑", "Select previous")),
+	Down:     key.NewBinding(key.WithKeys("down"), key.WithHelp("↓", "Select next")),
+	MoveUp:   key.NewBinding(key.WithKeys("shift+up"), key.WithHelp("shift+↑", "Merge earlier")),
+	MoveDown: key.NewBinding(key.WithKeys("shift+down"), key.WithHelp("shift+↓", "Merge later")),
+	Remove:   key.NewBinding(key.WithKeys("ctrl+x"), key.WithHelp("ctrl+x", "Remove selected")),
+}
+
+func init() {
+	helpers.RegisterKeys("valuesfiles", map[string]*key.Binding{"up": &ValuesFilesKeys.Up, "down": &ValuesFilesKeys.Down, "moveUp": &ValuesFilesKeys.MoveUp, "moveDown": &ValuesFilesKeys.MoveDown, "remove": &ValuesFilesKeys.Remove}, "global")
+}
+
+// ValuesFiles lists the local values files added in a wizard, in the order
+// helm merges them.
+type ValuesFiles struct {
+	Paths []string
+	// selected is the index of the selected path, -1 when none is.
+	selected int
+	// err is why the last path could not be added.
+	err error
+}
+
+// NewValuesFiles returns an empty list of values files.
+func NewValuesFiles() ValuesFiles {
+	return ValuesFiles{selected: -1}
+}
+
+// Add appends the file at path, ~ standing for the home directory, once it
+// is known to be a readable file.
+func (v *ValuesFiles) Add(path string) error {
+	v.err = v.add(path)
+	return v.err
+}
+
+func (v *ValuesFiles) add(path string) error {
+	path, err := filepath.Abs(expandHome(path))
+	if err != nil {
+		return err
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	if info.IsDir() {
+		return fmt.Errorf("%s is a directory", path)
+	}
+	for _, p := range v.Paths {
+		if p == path {
+			return errors.New(path + " is already listed")
+		}
+	}
+	v.Paths = append(v.Paths, path)
+	return nil
+}
+
+// Update handles msg when it edits the list, input being where paths are
+// typed, and reports whether it did.
+func (v *ValuesFiles) Update(input *textinput.Model, msg tea.KeyMsg) bool {
+	if input.Value() != "" || len(v.Paths) == 0 {
+		return false
+	}
+	switch {
+	case key.Matches(msg, ValuesFilesKeys.Up):
+		v.selected = max(v.selected, 0)
+		v.selected = (v.selected + len(v.Paths) - 1) % len(v.Paths)
+	case key.Matches(msg, ValuesFilesKeys.Down):
+		v.selected = (v.selected + 1) % len(v.Paths)
+	case key.Matches(msg, ValuesFilesKeys.MoveUp) && v.selected > 0:
+		v.Paths[v.selected-1], v.Paths[v.selected] = v.Paths[v.selected], v.Paths[v.selected-1]
+		v.selected--
+	case key.Matches(msg, ValuesFilesKeys.MoveDown) && v.selected >= 0 && v.selected < len(v.Paths)-1:
+		v.Paths[v.selected+1], v.Paths[v.selected] = v.Paths[v.selected], v.Paths[v.selected+1]
+		v.selected++
+	case key.Matches(msg, ValuesFilesKeys.Remove) && v.selected >= 0:
+		v.Paths = append(v.Paths[:v.selected], v.Paths[v.selected+1:]...)
+		v.selected = min(v.selected, len(v.Paths)-1)
+	default:
+		return false
+	}
+	v.err = nil
+	return true
+}
+
+// Seed returns the values the editor of a wizard starts from. Passed after
+// the listed files, they would override them all, so values are commented
+// out when files are listed, only the lines uncommented overriding them.
+func (v ValuesFiles) Seed(values string) string {
+	if len(v.Paths) == 0 {
+		return values
+	}
+	lines := []string{"# Passed after these values files, the values uncommented below override them:"}
+	for _, path := range v.Paths {
+		lines = append(lines, "#   "+path)
+	}
+	lines = append(lines, "#")
+	for _, line := range strings.Split(strings.TrimRight(values, "\n"), "\n") {
+		lines = append(lines, strings.TrimRight("# "+line, " "))
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// Reset empties the list.
+func (v *ValuesFiles) Reset() {
+	*v = NewValuesFiles()
+}
+
+// View shows the merge order of the files, later ones overriding earlier
+// ones, ending with the values edited in the wizard when edited is set.
+func (v ValuesFiles) View(edited bool) string {
+	var lines []string
+	if len(v.Paths) > 0 {
+		lines = append(lines, "Merge order, later files override earlier ones:")
+	}
+	selected := lipgloss.NewStyle().Foreground(styles.CurrentTheme.Highlight)
+	for i, path := range v.Paths {
+		line := fmt.Sprintf("  %d. %s", i+1, path)
+		if i == v.selected {
+			line = selected.Render(fmt.Sprintf("> %d. %s", i+1, path))
+		}
+		lines = append(lines, line)
+	}
+	if len(v.Paths) > 0 && edited {
+		lines = append(lines, fmt.Sprintf("  %d. values edited in the wizard", len(v.Paths)+1))
+	}
+	if v.err != nil {
+		lines = append(lines, lipgloss.NewStyle().Foreground(styles.CurrentTheme.Error).Render(v.err.Error()))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// PathSuggestions completes value, a path being typed, with the directories
+// and the YAML or JSON files it may name.
+func PathSuggestions(value string) []string {
+	if value == "" {
+		return nil
+	}
+	dir, prefix := filepath.Split(value)
+	entries, err := os.ReadDir(expandHome(dir + "."))
+	if err != nil {
+		return nil
+	}
+	var suggestions []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if !strings.HasPrefix(name, prefix) || (strings.HasPrefix(name, ".") && !strings.HasPrefix(prefix, ".")) {
+			continue
+		}
+		isDir := entry.IsDir()
+		if entry.Type()&os.ModeSymlink != 0 {
+			info, err := os.Stat(filepath.Join(expandHome(dir+"."), name))
+			isDir = err == nil && info.IsDir()
+		}
+		switch {
+		case isDir:
+			suggestions = append(suggestions, dir+name+"/")
+		case strings.HasSuffix(name, ".yaml"), strings.HasSuffix(name, ".yml"), strings.HasSuffix(name, ".json"):
+			suggestions = append(suggestions, dir+name)
+		}
+	}
+	sort.Strings(suggestions)
+	return suggestions
+}
+
+// expandHome replaces a leading ~ of path with the home directory.
+func expandHome(path string) string {
+	if path != "~" && !strings.HasPrefix(path, "~/") {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return home + path[1:]
+}
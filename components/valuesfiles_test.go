@@ -0,0 +1,86 @@
+package components
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/pidanou/helm-tui/helpers"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestPathSuggestions verifies that directories and YAML or JSON files are
+// suggested to complete a path.
+func TestPathSuggestions(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"values.yaml", "values-prod.yml", "values.txt", ".values.yaml"} {
+		assert.NoError(t, os.WriteFile(filepath.Join(dir, name), nil, 0644))
+	}
+	assert.NoError(t, os.Mkdir(filepath.Join(dir, "values.d"), 0755))
+
+	assert.Equal(t, []string{dir + "/values-prod.yml", dir + "/values.d/", dir + "/values.yaml"}, PathSuggestions(dir+"/val"))
+	assert.Equal(t, []string{dir + "/.values.yaml"}, PathSuggestions(dir+"/."))
+	assert.Empty(t, PathSuggestions(""))
+}
+
+// TestValuesFiles verifies that files are listed once, and that the selected
+// one can be moved or removed while the input is empty.
+func TestValuesFiles(t *testing.T) {
+	dir := t.TempDir()
+	base, prod := filepath.Join(dir, "base.yaml"), filepath.Join(dir, "prod.yaml")
+	assert.NoError(t, os.WriteFile(base, nil, 0644))
+	assert.NoError(t, os.WriteFile(prod, nil, 0644))
+	v := NewValuesFiles()
+	input := textinput.New()
+
+	assert.NoError(t, v.Add(prod))
+	assert.NoError(t, v.Add(base))
+	assert.Error(t, v.Add(base), "already listed")
+	assert.Error(t, v.Add(dir), "a directory")
+	assert.Contains(t, v.View(true), "3. values edited in the wizard")
+
+	assert.True(t, v.Update(&input, tea.KeyMsg{Type: tea.KeyUp}))
+	assert.True(t, v.Update(&input, tea.KeyMsg{Type: tea.KeyShiftUp}))
+	assert.Equal(t, []string{base, prod}, v.Paths)
+	assert.False(t, v.Update(&input, tea.KeyMsg{Type: tea.KeyShiftUp}), "the first file stays first")
+
+	assert.True(t, v.Update(&input, tea.KeyMsg{Type: tea.KeyCtrlX}))
+	assert.Equal(t, []string{prod}, v.Paths)
+	assert.NotContains(t, v.View(false), "edited")
+}
+
+// TestValuesFilesRemappedKeys verifies that the files are selected with the
+// keys bound to up and down.
+func TestValuesFilesRemappedKeys(t *testing.T) {
+	ValuesFilesKeys.Down.SetKeys("ctrl+j")
+	t.Cleanup(func() { ValuesFilesKeys.Down.SetKeys("down") })
+	file := filepath.Join(t.TempDir(), "base.yaml")
+	assert.NoError(t, os.WriteFile(file, nil, 0644))
+	v := NewValuesFiles()
+	input := textinput.New()
+	assert.NoError(t, v.Add(file))
+
+	assert.False(t, v.Update(&input, tea.KeyMsg{Type: tea.KeyDown}))
+	assert.True(t, v.Update(&input, tea.KeyMsg{Type: tea.KeyCtrlJ}))
+	assert.Contains(t, v.View(false), "> 1. "+file)
+}
+
+// TestValuesFilesSeed verifies that the values edited in a wizard are
+// commented out once files are listed, so that they do not override them.
+func TestValuesFilesSeed(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "prod.yaml")
+	assert.NoError(t, os.WriteFile(file, nil, 0644))
+	values := "# replicas\nreplicaCount: 1\n\nimage:\n  tag: latest\n"
+	v := NewValuesFiles()
+	assert.Equal(t, values, v.Seed(values))
+
+	assert.NoError(t, v.Add(file))
+	seeded := v.Seed(values)
+	assert.Contains(t, seeded, "#   "+file+"\n")
+	assert.Contains(t, seeded, "\n# # replicas\n# replicaCount: 1\n#\n# image:\n#   tag: latest\n")
+	seedFile := filepath.Join(t.TempDir(), "values.yaml")
+	assert.NoError(t, os.WriteFile(seedFile, []byte(seeded), 0644))
+	assert.NoError(t, helpers.ValidateYAMLFile(seedFile), "the seed should be valid, empty values")
+}
@@ -0,0 +1,222 @@
+// Package config loads the user configuration from ~/.helm-tui/config.yaml.
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/pidanou/helm-tui/styles"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	dirName  = ".helm-tui"
+	fileName = "config.yaml"
+)
+
+// Tabs are the names accepted by StartTab.
+var Tabs = []string{"releases", "repositories", "hub", "plugins", "activity"}
+
+// Duration is a time.Duration written as a string such as "30s" or "1m".
+type Duration struct {
+	time.Duration
+}
+
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.String())
+}
+
+func (d *Duration) UnmarshalJSON(b []byte) error {
+	var s string
+	err := json.Unmarshal(b, &s)
+	if err != nil {
+		return fmt.Errorf("duration must be a string such as \"30s\": %w", err)
+	}
+	d.Duration, err = time.ParseDuration(s)
+	return err
+}
+
+// ValuesStrategies are the accepted values of UpgradeDefaults.Values.
+var ValuesStrategies = []string{"reuse", "reset", "reset-then-reuse"}
+
+// UpgradeDefaults are the options the upgrade wizard starts from, named
+// after the flags of helm upgrade.
+type UpgradeDefaults struct {
+	Atomic        bool     `json:"atomic,omitempty"`
+	Wait          bool     `json:"wait,omitempty"`
+	WaitForJobs   bool     `json:"waitForJobs,omitempty"`
+	Timeout       Duration `json:"timeout"`
+	Force         bool     `json:"force,omitempty"`
+	CleanupOnFail bool     `json:"cleanupOnFail,omitempty"`
+	// Values is one of ValuesStrategies, helm's default when empty.
+	Values      string `json:"values,omitempty"`
+	HistoryMax  int    `json:"historyMax,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+type Config struct {
+	// DefaultNamespace is the namespace releases are installed into when
+	// none is given.
+	DefaultNamespace string `json:"defaultNamespace,omitempty"`
+	// KubeContext is the kubeconfig context used at startup, the current
+	// context when empty.
+	KubeContext string `json:"kubeContext,omitempty"`
+	// Editor is the command values files are edited with, $EDITOR when
+	// empty.
+	Editor string `json:"editor,omitempty"`
+	// StartTab is the tab shown at startup.
+	StartTab string `json:"startTab,omitempty"`
+	// RefreshInterval is how often the releases are listed again, never
+	// when zero.
+	RefreshInterval Duration `json:"refreshInterval"`
+	// HubURL is the base URL of the Artifact Hub instance searched by the
+	// Hub tab.
+	HubURL string `json:"hubURL,omitempty"`
+	// HelmBinary is the helm binary run by the exec backend.
+	HelmBinary string `json:"helmBinary,omitempty"`
+	// Theme names the color theme, picked from the terminal background
+	// when empty.
+	Theme string `json:"theme,omitempty"`
+	// ReadOnly refuses the actions changing releases, repositories or
+	// plugins.
+	ReadOnly bool `json:"readOnly,omitempty"`
+	// ReadOnlyContexts are the kube contexts always browsed read-only.
+	ReadOnlyContexts []string `json:"readOnlyContexts,omitempty"`
+	// ActivityLog appends the executed helm commands to activity.jsonl.
+	ActivityLog bool `json:"activityLog,omitempty"`
+	// UpgradeDefaults are the upgrade options by namespace, "*" standing
+	// for the namespaces not listed.
+	UpgradeDefaults map[string]UpgradeDefaults `json:"upgradeDefaults,omitempty"`
+}
+
+// Current is the configuration in use.
+var Current = Default()
+
+func Default() Config {
+	return Config{
+		StartTab:   "releases",
+		HubURL:     "https://artifacthub.io",
+		HelmBinary: "helm",
+	}
+}
+
+// Dir returns the helm-tui working directory, ~/.helm-tui.
+func Dir() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, dirName), nil
+}
+
+// Path returns the path of the configuration file.
+func Path() (string, error) {
+	dir, err := Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, fileName), nil
+}
+
+// Load reads a configuration file over the defaults. A missing file is not
+// an error.
+func Load(path string) (Config, error) {
+	cfg := Default()
+	content, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return cfg, nil
+	}
+	if err != nil {
+		return cfg, err
+	}
+	err = yaml.UnmarshalStrict(content, &cfg)
+	if err != nil {
+		return cfg, fmt.Errorf("%s: %w", path, err)
+	}
+	err = cfg.validate()
+	if err != nil {
+		return cfg, fmt.Errorf("%s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+func (c Config) validate() error {
+	if c.StartTab != "" && c.TabIndex() == -1 {
+		return fmt.Errorf("unknown startTab %q, expected one of %s", c.StartTab, strings.Join(Tabs, ", "))
+	}
+	if _, ok := styles.Themes[c.Theme]; c.Theme != "" && !ok {
+		return fmt.Errorf("unknown theme %q, expected one of %s", c.Theme, strings.Join(styles.ThemeNames(), ", "))
+	}
+	if strings.TrimSpace(c.HelmBinary) == "" {
+		return fmt.Errorf("helmBinary must not be blank")
+	}
+	if c.RefreshInterval.Duration < 0 {
+		return fmt.Errorf("refreshInterval must not be negative")
+	}
+	for namespace, d := range c.UpgradeDefaults {
+		if d.Values != "" && !slices.Contains(ValuesStrategies, d.Values) {
+			return fmt.Errorf("upgradeDefaults.%s: unknown values %q, expected one of %s", namespace, d.Values, strings.Join(ValuesStrategies, ", "))
+		}
+		if d.Timeout.Duration < 0 || d.HistoryMax < 0 {
+			return fmt.Errorf("upgradeDefaults.%s: timeout and historyMax must not be negative", namespace)
+		}
+	}
+	return nil
+}
+
+// TabIndex returns the position of StartTab in Tabs, -1 if unknown.
+func (c Config) TabIndex() int {
+	for i, tab := range Tabs {
+		if strings.EqualFold(tab, c.StartTab) {
+			return i
+		}
+	}
+	return -1
+}
+
+// ReadOnlyIn reports whether changes are refused in a kube context.
+func (c Config) ReadOnlyIn(kubeContext string) bool {
+	if c.ReadOnly {
+		return true
+	}
+	for _, name := range c.ReadOnlyContexts {
+		if name == kubeContext {
+			return true
+		}
+	}
+	return false
+}
+
+// Namespace returns the namespace to install into when none is given.
+func (c Config) Namespace() string {
+	if c.DefaultNamespace != "" {
+		return c.DefaultNamespace
+	}
+	return "default"
+}
+
+// UpgradeDefaultsIn returns the upgrade options to start from in namespace.
+func (c Config) UpgradeDefaultsIn(namespace string) UpgradeDefaults {
+	if d, ok := c.UpgradeDefaults[namespace]; ok {
+		return d
+	}
+	return c.UpgradeDefaults["*"]
+}
+
+// EditorCommand returns the editor command and its arguments, blank
+// editors being skipped.
+func (c Config) EditorCommand() []string {
+	for _, editor := range []string{c.Editor, os.Getenv("EDITOR")} {
+		if fields := strings.Fields(editor); len(fields) > 0 {
+			return fields
+		}
+	}
+	return []string{"vim"}
+}
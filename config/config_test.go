@@ -0,0 +1,102 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeConfig(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0600))
+	return path
+}
+
+// TestLoad verifies that the configuration file overrides the defaults.
+func TestLoad(t *testing.T) {
+	path := writeConfig(t, `
+defaultNamespace: payments
+kubeContext: staging
+editor: code --wait
+startTab: Plugins
+refreshInterval: 30s
+theme: high-contrast
+readOnlyContexts: [production]
+`)
+
+	cfg, err := Load(path)
+
+	require.NoError(t, err)
+	assert.Equal(t, "payments", cfg.Namespace())
+	assert.Equal(t, "staging", cfg.KubeContext)
+	assert.Equal(t, []string{"code", "--wait"}, cfg.EditorCommand())
+	assert.Equal(t, 3, cfg.TabIndex())
+	assert.Equal(t, 30*time.Second, cfg.RefreshInterval.Duration)
+	assert.Equal(t, "high-contrast", cfg.Theme)
+	assert.True(t, cfg.ReadOnlyIn("production"))
+	assert.False(t, cfg.ReadOnlyIn("staging"))
+	assert.Equal(t, "https://artifacthub.io", cfg.HubURL, "Unset options should keep their default")
+	assert.Equal(t, "helm", cfg.HelmBinary)
+}
+
+// TestLoadMissingFile verifies that a missing file yields the defaults.
+func TestLoadMissingFile(t *testing.T) {
+	cfg, err := Load(filepath.Join(t.TempDir(), "config.yaml"))
+
+	require.NoError(t, err)
+	assert.Equal(t, Default(), cfg)
+	assert.Equal(t, "default", cfg.Namespace())
+}
+
+// TestLoadInvalid verifies that mistakes in the file are reported.
+func TestLoadInvalid(t *testing.T) {
+	for _, content := range []string{
+		"startTab: charts",
+		"refreshInterval: 30",
+		"defaultNamspace: payments",
+		"theme: solarized",
+		`helmBinary: ""`,
+		`helmBinary: "  "`,
+		"upgradeDefaults: {prod: {values: keep}}",
+		"upgradeDefaults: {prod: {historyMax: -1}}",
+	} {
+		_, err := Load(writeConfig(t, content))
+		assert.Error(t, err, content)
+	}
+}
+
+// TestUpgradeDefaultsIn verifies that the upgrade defaults of a namespace
+// fall back to those of "*".
+func TestUpgradeDefaultsIn(t *testing.T) {
+	path := writeConfig(t, `
+upgradeDefaults:
+  "*": {historyMax: 5}
+  production: {atomic: true, timeout: 10m, values: reset-then-reuse}
+`)
+
+	cfg, err := Load(path)
+
+	require.NoError(t, err)
+	production := cfg.UpgradeDefaultsIn("production")
+	assert.True(t, production.Atomic)
+	assert.Equal(t, 10*time.Minute, production.Timeout.Duration)
+	assert.Equal(t, "reset-then-reuse", production.Values)
+	assert.Equal(t, 0, production.HistoryMax)
+	assert.Equal(t, 5, cfg.UpgradeDefaultsIn("staging").HistoryMax)
+	assert.Equal(t, UpgradeDefaults{}, Default().UpgradeDefaultsIn("staging"))
+}
+
+// TestEditorCommand verifies that blank editors fall back to $EDITOR, then
+// vim.
+func TestEditorCommand(t *testing.T) {
+	t.Setenv("EDITOR", "  ")
+	assert.Equal(t, []string{"vim"}, Config{Editor: " \t"}.EditorCommand())
+
+	t.Setenv("EDITOR", "nano -w")
+	assert.Equal(t, []string{"nano", "-w"}, Config{Editor: " "}.EditorCommand())
+	assert.Equal(t, []string{"hx"}, Config{Editor: "hx"}.EditorCommand())
+}
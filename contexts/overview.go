@@ -0,0 +1,67 @@
+package contexts
+
+import (
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/pidanou/helm-tui/components"
+	"github.com/pidanou/helm-tui/helm"
+	"github.com/pidanou/helm-tui/types"
+)
+
+var contextsCols = []components.ColumnDefinition{
+	{Title: "Current", Width: 9},
+	{Title: "Name", FlexFactor: 2},
+	{Title: "Cluster", FlexFactor: 2},
+	{Title: "User", FlexFactor: 2},
+	{Title: "Namespace", FlexFactor: 1},
+}
+
+// Model lists the contexts of the kubeconfig and switches the helm client to
+// the selected one.
+type Model struct {
+	client        helm.HelmClient
+	contextsTable table.Model
+	help          help.Model
+	keys          keyMap
+	width         int
+	height        int
+}
+
+func InitModel(client helm.HelmClient) Model {
+	t := components.GenerateTable()
+	t.Focus()
+	return Model{client: client, contextsTable: t, help: help.New(), keys: overviewKeys}
+}
+
+func (m Model) Init() tea.Cmd {
+	return m.list
+}
+
+func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
+	var cmd tea.Cmd
+
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		components.SetTable(&m.contextsTable, contextsCols, m.width)
+	case types.KubeContextsMsg:
+		m.contextsTable.SetRows(msg.Content)
+		for i, row := range msg.Content {
+			if row[1] == msg.Current {
+				m.contextsTable.SetCursor(i)
+			}
+		}
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, m.keys.Select):
+			return m, m.switchContext
+		case key.Matches(msg, m.keys.Cancel):
+			return m, func() tea.Msg { return types.CloseKubeContextsMsg{} }
+		}
+	}
+	m.contextsTable, cmd = m.contextsTable.Update(msg)
+	return m, cmd
+}
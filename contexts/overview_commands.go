@@ -0,0 +1,40 @@
+package contexts
+
+import (
+	"errors"
+
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/pidanou/helm-tui/kube"
+	"github.com/pidanou/helm-tui/types"
+)
+
+func (m Model) list() tea.Msg {
+	var rows = []table.Row{}
+
+	contexts, current, err := kube.LoadContexts(m.client.KubeConfig())
+	if err != nil {
+		return types.KubeContextsMsg{Err: err}
+	}
+	if active := m.client.KubeContext(); active != "" {
+		current = active
+	}
+
+	for _, ctx := range contexts {
+		marker := ""
+		if ctx.Name == current {
+			marker = "*"
+		}
+		rows = append(rows, table.Row{marker, ctx.Name, ctx.Cluster, ctx.User, ctx.Namespace})
+	}
+	return types.KubeContextsMsg{Content: rows, Current: current}
+}
+
+func (m Model) switchContext() tea.Msg {
+	if m.contextsTable.SelectedRow() == nil {
+		return types.KubeContextsMsg{Err: errors.New("No context selected")}
+	}
+	name := m.contextsTable.SelectedRow()[1]
+	m.client.SetKubeContext(name)
+	return types.SwitchKubeContextMsg{Context: name}
+}
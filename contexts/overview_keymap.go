@@ -0,0 +1,30 @@
+package contexts
+
+import (
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/pidanou/helm-tui/helpers"
+)
+
+type keyMap struct {
+	Select key.Binding
+	Cancel key.Binding
+}
+
+var overviewKeys = keyMap{
+	Select: key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "Switch context")),
+	Cancel: key.NewBinding(key.WithKeys("esc"), key.WithHelp("esc", "Close")),
+}
+
+func init() {
+	helpers.RegisterKeys("contexts", map[string]*key.Binding{"select": &overviewKeys.Select, "close": &overviewKeys.Cancel}, "table")
+}
+
+func (k keyMap) ShortHelp() []key.Binding {
+	return []key.Binding{k.Select, k.Cancel}
+}
+
+// FullHelp returns keybindings for the expanded help view. It's part of the
+// key.Map interface.
+func (k keyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{}
+}
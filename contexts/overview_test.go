@@ -0,0 +1,62 @@
+package contexts
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/pidanou/helm-tui/helm"
+	"github.com/pidanou/helm-tui/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const kubeconfig = `apiVersion: v1
+kind: Config
+current-context: staging
+clusters:
+- name: prod-cluster
+  cluster:
+    server: https://prod.example.com
+- name: staging-cluster
+  cluster:
+    server: https://staging.example.com
+users:
+- name: admin
+  user:
+    token: secret
+contexts:
+- name: staging
+  context:
+    cluster: staging-cluster
+    user: admin
+- name: prod
+  context:
+    cluster: prod-cluster
+    user: admin
+`
+
+// TestSwitchContext verifies that selecting a context switches the helm client to it.
+func TestSwitchContext(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config")
+	require.NoError(t, os.WriteFile(path, []byte(kubeconfig), 0600))
+	t.Setenv("KUBECONFIG", path)
+	client := helm.NewFakeClient()
+	m := InitModel(client)
+	m, _ = m.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+
+	msg := m.list().(types.KubeContextsMsg)
+	m, _ = m.Update(msg)
+
+	assert.NoError(t, msg.Err)
+	assert.Equal(t, "staging", msg.Current)
+	assert.Equal(t, "staging", m.contextsTable.SelectedRow()[1], "The active context should be selected")
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyUp})
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+
+	assert.Equal(t, types.SwitchKubeContextMsg{Context: "prod"}, cmd())
+	assert.Equal(t, "prod", client.KubeContext())
+	assert.Equal(t, "prod", m.list().(types.KubeContextsMsg).Current)
+}
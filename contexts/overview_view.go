@@ -0,0 +1,15 @@
+package contexts
+
+import (
+	"github.com/charmbracelet/lipgloss"
+	"github.com/pidanou/helm-tui/styles"
+)
+
+func (m Model) View() string {
+	t := m.contextsTable
+	t.SetHeight(m.height - 3)
+	t.SetWidth(m.width - 2)
+	topBorder := styles.GenerateTopBorderWithTitle(" Kube contexts ", t.Width(), styles.Border, styles.ActiveStyle)
+	view := styles.ActiveStyle.Border(styles.Border, false, true, true).Render(t.View())
+	return lipgloss.JoinVertical(lipgloss.Left, topBorder, view, m.help.View(m.keys))
+}
@@ -0,0 +1,117 @@
+package helm
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// ActivityLogSize is the number of commands kept in memory.
+	ActivityLogSize = 500
+	// excerptSize is the number of bytes of stdout and stderr kept per command.
+	excerptSize = 4096
+)
+
+// Activity is a helm command issued by a client.
+type Activity struct {
+	Command  []string      `json:"command"`
+	Start    time.Time     `json:"start"`
+	Duration time.Duration `json:"duration"`
+	ExitCode int           `json:"exit_code"`
+	Stdout   string        `json:"stdout,omitempty"`
+	Stderr   string        `json:"stderr,omitempty"`
+}
+
+// ActivityLog is a ring buffer of the last commands issued by a client,
+// optionally appended to a JSONL file.
+type ActivityLog struct {
+	mu      sync.Mutex
+	entries []Activity
+	next    int
+	total   int
+	file    string
+}
+
+func NewActivityLog(size int) *ActivityLog {
+	return &ActivityLog{entries: make([]Activity, 0, size)}
+}
+
+func excerpt(s string) string {
+	if len(s) <= excerptSize {
+		return s
+	}
+	return s[:excerptSize] + "\n[truncated]"
+}
+
+// Add records an activity, evicting the oldest one when the log is full.
+func (l *ActivityLog) Add(a Activity) {
+	a.Stdout = excerpt(a.Stdout)
+	a.Stderr = excerpt(a.Stderr)
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if len(l.entries) < cap(l.entries) {
+		l.entries = append(l.entries, a)
+	} else {
+		l.entries[l.next] = a
+		l.next = (l.next + 1) % len(l.entries)
+	}
+	l.total++
+	if l.file != "" {
+		// the in-memory log stays usable if the file cannot be written
+		_ = appendActivities(l.file, a)
+	}
+}
+
+// Entries returns the recorded activities, oldest first.
+func (l *ActivityLog) Entries() []Activity {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.ordered()
+}
+
+func (l *ActivityLog) ordered() []Activity {
+	entries := make([]Activity, 0, len(l.entries))
+	entries = append(entries, l.entries[l.next:]...)
+	return append(entries, l.entries[:l.next]...)
+}
+
+// Total returns the number of activities recorded since the log was
+// created, including the evicted ones.
+func (l *ActivityLog) Total() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.total
+}
+
+// Persist appends the activities in memory, then every new one, to a JSONL
+// file.
+func (l *ActivityLog) Persist(file string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.file = file
+	return appendActivities(file, l.ordered()...)
+}
+
+func appendActivities(file string, activities ...Activity) error {
+	f, err := os.OpenFile(file, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	encoder := json.NewEncoder(f)
+	for _, a := range activities {
+		err = encoder.Encode(a)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CommandLine returns the command as it would be typed in a shell.
+func (a Activity) CommandLine() string {
+	return strings.Join(a.Command, " ")
+}
@@ -0,0 +1,47 @@
+package helm
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestActivityLogEvictsOldest verifies that the log keeps the most recent activities in order.
+func TestActivityLogEvictsOldest(t *testing.T) {
+	log := NewActivityLog(2)
+
+	log.Add(Activity{Command: []string{"helm", "ls"}})
+	log.Add(Activity{Command: []string{"helm", "repo", "ls"}})
+	log.Add(Activity{Command: []string{"helm", "plugin", "ls"}})
+
+	entries := log.Entries()
+	assert.Len(t, entries, 2)
+	assert.Equal(t, "helm repo ls", entries[0].CommandLine())
+	assert.Equal(t, "helm plugin ls", entries[1].CommandLine())
+	assert.Equal(t, 3, log.Total())
+}
+
+// TestActivityLogPersist verifies that past and future activities are appended to the JSONL file.
+func TestActivityLogPersist(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "activity.jsonl")
+	log := NewActivityLog(10)
+	log.Add(Activity{Command: []string{"helm", "ls"}})
+
+	require.NoError(t, log.Persist(file))
+	log.Add(Activity{Command: []string{"helm", "uninstall", "web"}, ExitCode: 1, Stderr: strings.Repeat("x", excerptSize+1)})
+
+	content, err := os.ReadFile(file)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(content)), "\n")
+	assert.Len(t, lines, 2)
+	var activity Activity
+	require.NoError(t, json.Unmarshal([]byte(lines[1]), &activity))
+	assert.Equal(t, []string{"helm", "uninstall", "web"}, activity.Command)
+	assert.Equal(t, 1, activity.ExitCode)
+	assert.True(t, strings.HasSuffix(activity.Stderr, "[truncated]"), "Output should be cut to an excerpt")
+}
@@ -0,0 +1,156 @@
+package helm
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/pidanou/helm-tui/types"
+)
+
+// HelmClient is the set of helm operations used by the tabs. Models receive
+// a HelmClient at construction time instead of invoking helm directly, so
+// the backend can be swapped (e.g. for the in-memory FakeClient in tests).
+type HelmClient interface {
+	// ListReleases lists the releases of a namespace, or of every namespace
+	// when namespace is empty.
+	ListReleases(namespace string) ([]types.Release, error)
+	History(release, namespace string) ([]types.History, error)
+	GetNotes(release, namespace string) (string, error)
+	GetMetadata(release, namespace string) (string, error)
+	GetHooks(release, namespace string) (string, error)
+	GetValues(release, namespace string) (string, error)
+	GetManifest(release, namespace string) (string, error)
+	// GetRevisionValues and GetRevisionManifest return the values and the
+	// manifest of a past revision of a release.
+	GetRevisionValues(release, namespace string, revision int) (string, error)
+	GetRevisionManifest(release, namespace string, revision int) (string, error)
+	Install(opts InstallOptions) error
+	// PreviewInstall renders an install, as helm install --dry-run, without
+	// applying it.
+	PreviewInstall(opts InstallOptions) (Preview, error)
+	// Template renders the manifest of a chart, as helm template, without
+	// contacting the cluster.
+	Template(opts InstallOptions) (string, error)
+	Upgrade(opts UpgradeOptions) error
+	// PreviewUpgrade renders an upgrade, as helm upgrade --dry-run, without
+	// applying it.
+	PreviewUpgrade(opts UpgradeOptions) (Preview, error)
+	Rollback(release, revision, namespace string) error
+	Uninstall(release, namespace string) error
+
+	ShowValues(chart, version string) (string, error)
+	// ShowSchema returns the values.schema.json of a chart, "" when it has
+	// none.
+	ShowSchema(chart, version string) (string, error)
+	SearchRepo(opts SearchOptions) ([]types.Pkg, error)
+
+	RepoList() ([]types.Repository, error)
+	// RepoUpdate updates the given repositories, or all of them when none
+	// is given.
+	RepoUpdate(names ...string) error
+	RepoAdd(name, url string) error
+	RepoRemove(name string) error
+
+	PluginList() ([]types.Plugin, error)
+	PluginInstall(source string) error
+	PluginUpdate(name string) error
+	PluginUninstall(name string) error
+
+	// ListNamespaces returns the namespaces of the cluster of the current
+	// kube context.
+	ListNamespaces() ([]string, error)
+
+	// Activity returns the log of the commands issued by the client.
+	Activity() *ActivityLog
+
+	// KubeContext returns the kubeconfig context releases are managed in,
+	// "" meaning the kubeconfig's current context.
+	KubeContext() string
+	SetKubeContext(name string)
+	// KubeConfig returns the path of the kubeconfig file, "" meaning
+	// $KUBECONFIG or ~/.kube/config.
+	KubeConfig() string
+	SetKubeConfig(path string)
+}
+
+type InstallOptions struct {
+	ReleaseName string
+	Chart       string
+	Version     string
+	Namespace   string
+	// ValuesFiles are layered in order under ValuesFile, the values edited
+	// in the wizard.
+	ValuesFiles []string
+	ValuesFile  string
+	// Set, SetString and SetJSON are key=value overrides, as given to
+	// --set, --set-string and --set-json.
+	Set       []string
+	SetString []string
+	SetJSON   []string
+}
+
+type UpgradeOptions struct {
+	ReleaseName string
+	Chart       string
+	Version     string
+	Namespace   string
+	// ValuesFiles are layered in order under ValuesFile, the values edited
+	// in the wizard.
+	ValuesFiles []string
+	ValuesFile  string
+	// Set, SetString and SetJSON are key=value overrides, as given to
+	// --set, --set-string and --set-json.
+	Set       []string
+	SetString []string
+	SetJSON   []string
+	// The fields below are the flags of helm upgrade of the same name, left
+	// out when zero.
+	Atomic        bool
+	Wait          bool
+	WaitForJobs   bool
+	Timeout       time.Duration
+	Force         bool
+	CleanupOnFail bool
+	// Values tells which values the upgrade starts from, helm's default
+	// when empty.
+	Values      ValuesStrategy
+	HistoryMax  int
+	Description string
+}
+
+// ValuesStrategy tells which values an upgrade starts from.
+type ValuesStrategy string
+
+const (
+	ReuseValues          ValuesStrategy = "reuse"
+	ResetValues          ValuesStrategy = "reset"
+	ResetThenReuseValues ValuesStrategy = "reset-then-reuse"
+)
+
+// ValuesStrategies are the accepted values strategies, in the order the
+// upgrade options cycle through them.
+var ValuesStrategies = []ValuesStrategy{ReuseValues, ResetValues, ResetThenReuseValues}
+
+// Preview is what an install or upgrade would apply.
+type Preview struct {
+	Manifest string
+	Notes    string
+}
+
+type SearchOptions struct {
+	Keyword  string
+	Regexp   bool
+	Versions bool
+}
+
+// NewClient returns the HelmClient for a backend name: "exec" runs the helm
+// binary, "sdk" uses the helm Go SDK.
+func NewClient(backend string) (HelmClient, error) {
+	switch backend {
+	case "exec", "":
+		return NewExecClient(), nil
+	case "sdk":
+		return NewSDKClient(), nil
+	}
+	return nil, fmt.Errorf("unknown backend %q, expected exec or sdk", backend)
+}
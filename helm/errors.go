@@ -0,0 +1,30 @@
+package helm
+
+import (
+	"strings"
+)
+
+// CommandError is returned by ExecClient when a helm command fails. It keeps
+// the command line, the exit code and what helm printed on stderr.
+type CommandError struct {
+	Args     []string
+	ExitCode int
+	Stderr   string
+	Err      error
+}
+
+func (e *CommandError) Error() string {
+	if stderr := strings.TrimSpace(e.Stderr); stderr != "" {
+		return stderr
+	}
+	return e.Err.Error()
+}
+
+func (e *CommandError) Unwrap() error {
+	return e.Err
+}
+
+// Command returns the command line that failed.
+func (e *CommandError) Command() string {
+	return strings.Join(e.Args, " ")
+}
@@ -0,0 +1,463 @@
+package helm
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pidanou/helm-tui/kube"
+	"github.com/pidanou/helm-tui/types"
+	"helm.sh/helm/v3/pkg/chart/loader"
+)
+
+// ExecClient implements HelmClient by running the helm binary.
+type ExecClient struct {
+	Binary      string
+	activity    *ActivityLog
+	mu          sync.RWMutex
+	kubeContext string
+	kubeConfig  string
+}
+
+func NewExecClient() *ExecClient {
+	return &ExecClient{Binary: "helm", activity: NewActivityLog(ActivityLogSize)}
+}
+
+func (c *ExecClient) run(args ...string) ([]byte, error) {
+	var stdout, stderr bytes.Buffer
+
+	if kubeContext := c.KubeContext(); kubeContext != "" {
+		args = append(args, "--kube-context", kubeContext)
+	}
+	if kubeConfig := c.KubeConfig(); kubeConfig != "" {
+		args = append(args, "--kubeconfig", kubeConfig)
+	}
+	cmd := exec.Command(c.Binary, args...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	start := time.Now()
+	err := cmd.Run()
+	exitCode := 0
+	if err != nil {
+		exitCode = -1
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			exitCode = exitErr.ExitCode()
+		}
+	}
+	if c.activity != nil {
+		c.activity.Add(Activity{Command: cmd.Args, Start: start, Duration: time.Since(start), ExitCode: exitCode, Stdout: stdout.String(), Stderr: stderr.String()})
+	}
+	if err != nil {
+		return nil, &CommandError{Args: cmd.Args, ExitCode: exitCode, Stderr: stderr.String(), Err: err}
+	}
+	return stdout.Bytes(), nil
+}
+
+func (c *ExecClient) Activity() *ActivityLog {
+	return c.activity
+}
+
+func (c *ExecClient) KubeContext() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.kubeContext
+}
+
+func (c *ExecClient) SetKubeContext(name string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.kubeContext = name
+}
+
+func (c *ExecClient) KubeConfig() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.kubeConfig
+}
+
+func (c *ExecClient) SetKubeConfig(path string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.kubeConfig = path
+}
+
+func (c *ExecClient) ListNamespaces() ([]string, error) {
+	config, err := kube.RESTConfig(c.KubeConfig(), c.KubeContext())
+	if err != nil {
+		return nil, err
+	}
+	return kube.ListNamespaces(config)
+}
+
+// listArgs lists the pending releases along with helm's default deployed
+// and failed ones.
+func listArgs(namespace string) []string {
+	args := []string{"ls", "--deployed", "--failed", "--pending", "--output", "json"}
+	if namespace == "" {
+		return append(args, "--all-namespaces")
+	}
+	return append(args, "--namespace", namespace)
+}
+
+func (c *ExecClient) ListReleases(namespace string) ([]types.Release, error) {
+	out, err := c.run(listArgs(namespace)...)
+	if err != nil {
+		return nil, err
+	}
+	var releases []types.Release
+	err = json.Unmarshal(out, &releases)
+	if err != nil {
+		return nil, err
+	}
+	return releases, nil
+}
+
+func (c *ExecClient) History(release, namespace string) ([]types.History, error) {
+	out, err := c.run("history", release, "--namespace", namespace, "--output", "json")
+	if err != nil {
+		return nil, err
+	}
+	var history []types.History
+	err = json.Unmarshal(out, &history)
+	if err != nil {
+		return nil, err
+	}
+	return history, nil
+}
+
+func (c *ExecClient) get(kind, release, namespace string, flags ...string) (string, error) {
+	out, err := c.run(append([]string{"get", kind, release, "--namespace", namespace}, flags...)...)
+	if err != nil {
+		return "", err
+	}
+	return string(out), nil
+}
+
+func (c *ExecClient) GetNotes(release, namespace string) (string, error) {
+	return c.get("notes", release, namespace)
+}
+
+func (c *ExecClient) GetMetadata(release, namespace string) (string, error) {
+	return c.get("metadata", release, namespace)
+}
+
+func (c *ExecClient) GetHooks(release, namespace string) (string, error) {
+	return c.get("hooks", release, namespace)
+}
+
+func (c *ExecClient) GetValues(release, namespace string) (string, error) {
+	out, err := c.get("values", release, namespace)
+	if err != nil {
+		return "", err
+	}
+	return userValues(out)
+}
+
+func (c *ExecClient) GetManifest(release, namespace string) (string, error) {
+	return c.get("manifest", release, namespace)
+}
+
+func (c *ExecClient) GetRevisionValues(release, namespace string, revision int) (string, error) {
+	out, err := c.get("values", release, namespace, "--revision", strconv.Itoa(revision))
+	if err != nil {
+		return "", err
+	}
+	return userValues(out)
+}
+
+func (c *ExecClient) GetRevisionManifest(release, namespace string, revision int) (string, error) {
+	return c.get("manifest", release, namespace, "--revision", strconv.Itoa(revision))
+}
+
+// userValues drops the "USER-SUPPLIED VALUES:" header of helm get values.
+func userValues(out string) (string, error) {
+	lines := strings.Split(out, "\n")
+	if len(lines) <= 1 {
+		return "", errors.New("no values found")
+	}
+	return strings.Join(lines[1:], "\n"), nil
+}
+
+// overrideArgs returns the flags setting the key=value overrides.
+func overrideArgs(set, setString, setJSON []string) []string {
+	var args []string
+	for _, o := range set {
+		args = append(args, "--set", o)
+	}
+	for _, o := range setString {
+		args = append(args, "--set-string", o)
+	}
+	for _, o := range setJSON {
+		args = append(args, "--set-json", o)
+	}
+	return args
+}
+
+// valueFiles lists the values files in the order helm merges them, file
+// last.
+func valueFiles(files []string, file string) []string {
+	if file == "" {
+		return files
+	}
+	return append(append([]string{}, files...), file)
+}
+
+func installArgs(opts InstallOptions) []string {
+	args := []string{"install", opts.ReleaseName, opts.Chart}
+	if opts.Version != "" {
+		args = append(args, "--version", opts.Version)
+	}
+	for _, file := range valueFiles(opts.ValuesFiles, opts.ValuesFile) {
+		args = append(args, "--values", file)
+	}
+	args = append(args, overrideArgs(opts.Set, opts.SetString, opts.SetJSON)...)
+	return append(args, "--namespace", opts.Namespace, "--create-namespace")
+}
+
+func (c *ExecClient) Install(opts InstallOptions) error {
+	_, err := c.run(installArgs(opts)...)
+	return err
+}
+
+func (c *ExecClient) PreviewInstall(opts InstallOptions) (Preview, error) {
+	out, err := c.run(append(installArgs(opts), "--dry-run", "--output", "json")...)
+	if err != nil {
+		return Preview{}, err
+	}
+	return parsePreview(out)
+}
+
+func templateArgs(opts InstallOptions) []string {
+	args := []string{"template", opts.ReleaseName, opts.Chart}
+	if opts.Version != "" {
+		args = append(args, "--version", opts.Version)
+	}
+	for _, file := range valueFiles(opts.ValuesFiles, opts.ValuesFile) {
+		args = append(args, "--values", file)
+	}
+	args = append(args, overrideArgs(opts.Set, opts.SetString, opts.SetJSON)...)
+	return append(args, "--namespace", opts.Namespace)
+}
+
+func (c *ExecClient) Template(opts InstallOptions) (string, error) {
+	out, err := c.run(templateArgs(opts)...)
+	return string(out), err
+}
+
+func upgradeArgs(opts UpgradeOptions) []string {
+	args := []string{"upgrade", opts.ReleaseName, opts.Chart}
+	if opts.Version != "" {
+		args = append(args, "--version", opts.Version)
+	}
+	for _, file := range valueFiles(opts.ValuesFiles, opts.ValuesFile) {
+		args = append(args, "--values", file)
+	}
+	args = append(args, overrideArgs(opts.Set, opts.SetString, opts.SetJSON)...)
+	flags := []struct {
+		name string
+		set  bool
+	}{
+		{"--atomic", opts.Atomic},
+		{"--wait", opts.Wait},
+		{"--wait-for-jobs", opts.WaitForJobs},
+		{"--force", opts.Force},
+		{"--cleanup-on-fail", opts.CleanupOnFail},
+	}
+	for _, flag := range flags {
+		if flag.set {
+			args = append(args, flag.name)
+		}
+	}
+	if opts.Timeout != 0 {
+		args = append(args, "--timeout", opts.Timeout.String())
+	}
+	if opts.Values != "" {
+		args = append(args, "--"+string(opts.Values)+"-values")
+	}
+	if opts.HistoryMax != 0 {
+		args = append(args, "--history-max", strconv.Itoa(opts.HistoryMax))
+	}
+	if opts.Description != "" {
+		args = append(args, "--description", opts.Description)
+	}
+	return append(args, "--namespace", opts.Namespace)
+}
+
+func (c *ExecClient) Upgrade(opts UpgradeOptions) error {
+	_, err := c.run(upgradeArgs(opts)...)
+	return err
+}
+
+func (c *ExecClient) PreviewUpgrade(opts UpgradeOptions) (Preview, error) {
+	out, err := c.run(append(upgradeArgs(opts), "--dry-run", "--output", "json")...)
+	if err != nil {
+		return Preview{}, err
+	}
+	return parsePreview(out)
+}
+
+// parsePreview reads the manifest and notes of a release printed by
+// helm --output json.
+func parsePreview(out []byte) (Preview, error) {
+	var rel struct {
+		Manifest string `json:"manifest"`
+		Info     struct {
+			Notes string `json:"notes"`
+		} `json:"info"`
+	}
+	if err := json.Unmarshal(out, &rel); err != nil {
+		return Preview{}, err
+	}
+	return Preview{Manifest: rel.Manifest, Notes: rel.Info.Notes}, nil
+}
+
+func (c *ExecClient) Rollback(release, revision, namespace string) error {
+	_, err := c.run("rollback", release, revision, "--namespace", namespace)
+	return err
+}
+
+func (c *ExecClient) Uninstall(release, namespace string) error {
+	_, err := c.run("uninstall", release, "--namespace", namespace)
+	return err
+}
+
+func (c *ExecClient) ShowValues(chart, version string) (string, error) {
+	out, err := c.run("show", "values", chart, "--version", version)
+	if err != nil {
+		return "", err
+	}
+	return string(out), nil
+}
+
+// ShowSchema reads the schema of a local chart, or of a chart pulled to a
+// temporary directory, helm show having no command for it.
+func (c *ExecClient) ShowSchema(chart, version string) (string, error) {
+	path := chart
+	if _, err := os.Stat(chart); err != nil {
+		dir, err := os.MkdirTemp("", "helm-tui-schema")
+		if err != nil {
+			return "", err
+		}
+		defer os.RemoveAll(dir)
+		args := []string{"pull", chart, "--untar", "--untardir", dir}
+		if version != "" {
+			args = append(args, "--version", version)
+		}
+		if _, err := c.run(args...); err != nil {
+			return "", err
+		}
+		path = filepath.Join(dir, filepath.Base(chart))
+	}
+	chrt, err := loader.Load(path)
+	if err != nil {
+		return "", err
+	}
+	return string(chrt.Schema), nil
+}
+
+func searchArgs(opts SearchOptions) []string {
+	args := []string{"search", "repo"}
+	if opts.Regexp {
+		args = append(args, "--regexp")
+	}
+	args = append(args, opts.Keyword)
+	if opts.Versions {
+		args = append(args, "--versions")
+	}
+	return append(args, "--output", "json")
+}
+
+func (c *ExecClient) SearchRepo(opts SearchOptions) ([]types.Pkg, error) {
+	out, err := c.run(searchArgs(opts)...)
+	if err != nil {
+		return nil, err
+	}
+	var pkgs []types.Pkg
+	err = json.Unmarshal(out, &pkgs)
+	if err != nil {
+		return nil, err
+	}
+	return pkgs, nil
+}
+
+func (c *ExecClient) RepoList() ([]types.Repository, error) {
+	out, err := c.run("repo", "ls", "--output", "json")
+	if err != nil {
+		return nil, err
+	}
+	var repos []types.Repository
+	err = json.Unmarshal(out, &repos)
+	if err != nil {
+		return nil, err
+	}
+	return repos, nil
+}
+
+func (c *ExecClient) RepoUpdate(names ...string) error {
+	_, err := c.run(append([]string{"repo", "update"}, names...)...)
+	return err
+}
+
+func (c *ExecClient) RepoAdd(name, url string) error {
+	_, err := c.run("repo", "add", name, url)
+	return err
+}
+
+func (c *ExecClient) RepoRemove(name string) error {
+	_, err := c.run("repo", "remove", name)
+	return err
+}
+
+// parsePluginList parses the table printed by `helm plugin ls`, which has no
+// structured output.
+func parsePluginList(out string) []types.Plugin {
+	plugins := []types.Plugin{}
+	lines := strings.Split(out, "\n")
+	if len(lines) < 2 {
+		return plugins
+	}
+	for _, line := range lines[1 : len(lines)-1] {
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+		plugins = append(plugins, types.Plugin{
+			Name:        fields[0],
+			Version:     fields[1],
+			Description: strings.Join(fields[2:], " "),
+		})
+	}
+	return plugins
+}
+
+func (c *ExecClient) PluginList() ([]types.Plugin, error) {
+	out, err := c.run("plugin", "ls")
+	if err != nil {
+		return nil, err
+	}
+	return parsePluginList(string(out)), nil
+}
+
+func (c *ExecClient) PluginInstall(source string) error {
+	_, err := c.run("plugin", "install", strings.TrimSpace(source))
+	return err
+}
+
+func (c *ExecClient) PluginUpdate(name string) error {
+	_, err := c.run("plugin", "update", name)
+	return err
+}
+
+func (c *ExecClient) PluginUninstall(name string) error {
+	_, err := c.run("plugin", "uninstall", strings.TrimSpace(name))
+	return err
+}
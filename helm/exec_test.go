@@ -0,0 +1,103 @@
+package helm
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pidanou/helm-tui/types"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestInstallArgs verifies that optional flags are only passed when set, and
+// that the values files are passed in merge order.
+func TestInstallArgs(t *testing.T) {
+	args := installArgs(InstallOptions{ReleaseName: "web", Chart: "bitnami/nginx", Namespace: "default"})
+	assert.Equal(t, []string{"install", "web", "bitnami/nginx", "--namespace", "default", "--create-namespace"}, args)
+
+	args = installArgs(InstallOptions{ReleaseName: "web", Chart: "bitnami/nginx", Version: "1.0.0", Namespace: "web", ValuesFile: "/tmp/values.yaml"})
+	assert.Equal(t, []string{"install", "web", "bitnami/nginx", "--version", "1.0.0", "--values", "/tmp/values.yaml", "--namespace", "web", "--create-namespace"}, args)
+
+	args = installArgs(InstallOptions{ReleaseName: "web", Chart: "bitnami/nginx", Namespace: "web", ValuesFiles: []string{"base.yaml", "prod.yaml"}, ValuesFile: "/tmp/values.yaml"})
+	assert.Equal(t, []string{"install", "web", "bitnami/nginx", "--values", "base.yaml", "--values", "prod.yaml", "--values", "/tmp/values.yaml", "--namespace", "web", "--create-namespace"}, args)
+}
+
+// TestUpgradeArgs verifies that the requested version, the overrides and the
+// upgrade options are passed to helm upgrade.
+func TestUpgradeArgs(t *testing.T) {
+	args := upgradeArgs(UpgradeOptions{ReleaseName: "web", Chart: "bitnami/nginx", Version: "2.0.0", Namespace: "web"})
+	assert.Equal(t, []string{"upgrade", "web", "bitnami/nginx", "--version", "2.0.0", "--namespace", "web"}, args)
+
+	args = upgradeArgs(UpgradeOptions{ReleaseName: "web", Chart: "bitnami/nginx", Namespace: "web", Set: []string{"a=1", "b=2"}, SetJSON: []string{`c={"d":1}`}})
+	assert.Equal(t, []string{"upgrade", "web", "bitnami/nginx", "--set", "a=1", "--set", "b=2", "--set-json", `c={"d":1}`, "--namespace", "web"}, args)
+
+	args = upgradeArgs(UpgradeOptions{ReleaseName: "web", Chart: "bitnami/nginx", Namespace: "web", Atomic: true, WaitForJobs: true, Timeout: 10 * time.Minute, Values: ResetThenReuseValues, HistoryMax: 5, Description: "hotfix"})
+	assert.Equal(t, []string{"upgrade", "web", "bitnami/nginx", "--atomic", "--wait-for-jobs", "--timeout", "10m0s", "--reset-then-reuse-values", "--history-max", "5", "--description", "hotfix", "--namespace", "web"}, args)
+}
+
+// TestSearchArgs verifies the helm search repo arguments.
+func TestSearchArgs(t *testing.T) {
+	args := searchArgs(SearchOptions{Keyword: "nginx", Regexp: true, Versions: true})
+	assert.Equal(t, []string{"search", "repo", "--regexp", "nginx", "--versions", "--output", "json"}, args)
+}
+
+// TestListArgs verifies that pending releases are listed along with helm's
+// default deployed and failed ones.
+func TestListArgs(t *testing.T) {
+	assert.Equal(t, []string{"ls", "--deployed", "--failed", "--pending", "--output", "json", "--all-namespaces"}, listArgs(""))
+	assert.Equal(t, []string{"ls", "--deployed", "--failed", "--pending", "--output", "json", "--namespace", "apps"}, listArgs("apps"))
+}
+
+// TestParsePluginList verifies that the helm plugin ls table is parsed.
+func TestParsePluginList(t *testing.T) {
+	out := "NAME\tVERSION\tDESCRIPTION\ndiff\t3.9.11\tPreview helm upgrade changes as a diff\ntui\t0.5.0\tSimple terminal UI for Helm\n"
+
+	plugins := parsePluginList(out)
+
+	assert.Equal(t, []types.Plugin{
+		{Name: "diff", Version: "3.9.11", Description: "Preview helm upgrade changes as a diff"},
+		{Name: "tui", Version: "0.5.0", Description: "Simple terminal UI for Helm"},
+	}, plugins)
+	assert.Empty(t, parsePluginList(""), "Empty output should yield no plugins")
+}
+
+// TestRunCommandError verifies that a failing command reports its arguments, exit code and stderr.
+func TestRunCommandError(t *testing.T) {
+	c := &ExecClient{Binary: "sh"}
+
+	_, err := c.run("-c", "echo 'Error: release: not found' >&2; exit 3")
+
+	var cmdErr *CommandError
+	assert.ErrorAs(t, err, &cmdErr)
+	assert.Equal(t, []string{"sh", "-c", "echo 'Error: release: not found' >&2; exit 3"}, cmdErr.Args)
+	assert.Equal(t, 3, cmdErr.ExitCode)
+	assert.Equal(t, "Error: release: not found", err.Error())
+}
+
+// TestRunClusterFlags verifies that the kube context and kubeconfig are passed to every command.
+func TestRunClusterFlags(t *testing.T) {
+	c := &ExecClient{Binary: "echo"}
+	c.SetKubeContext("staging")
+	c.SetKubeConfig("/tmp/kubeconfig")
+
+	out, err := c.run("ls")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "ls --kube-context staging --kubeconfig /tmp/kubeconfig\n", string(out))
+}
+
+// TestGetRevision verifies that the revision is passed to helm get.
+func TestGetRevision(t *testing.T) {
+	c := &ExecClient{Binary: "echo", activity: NewActivityLog(ActivityLogSize)}
+
+	manifest, err := c.GetRevisionManifest("web", "default", 3)
+	assert.NoError(t, err)
+	assert.Equal(t, "get manifest web --namespace default --revision 3\n", manifest)
+}
+
+// TestParsePreview verifies that the manifest and notes of a dry run are read
+// from helm's JSON output.
+func TestParsePreview(t *testing.T) {
+	preview, err := parsePreview([]byte(`{"name":"web","info":{"status":"pending-upgrade","notes":"Visit http://web"},"manifest":"---\nkind: Service\n"}`))
+	assert.NoError(t, err)
+	assert.Equal(t, Preview{Manifest: "---\nkind: Service\n", Notes: "Visit http://web"}, preview)
+}
@@ -0,0 +1,432 @@
+package helm
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pidanou/helm-tui/types"
+)
+
+// FakeClient is an in-memory HelmClient for tests. Release details (notes,
+// values, ...) are keyed by "namespace/name".
+type FakeClient struct {
+	mu           sync.Mutex
+	Releases     []types.Release
+	Histories    map[string][]types.History
+	Notes        map[string]string
+	Metadata     map[string]string
+	Hooks        map[string]string
+	Values       map[string]string
+	Manifests    map[string]string
+	Previews     map[string]Preview
+	Templates    map[string]string
+	Schemas      map[string]string
+	ChartValues  map[string]string
+	Repositories []types.Repository
+	Packages     []types.Pkg
+	Plugins      []types.Plugin
+	Namespaces   []string
+	Context      string
+	Kubeconfig   string
+	// Err, when set, is returned by every call.
+	Err error
+	// Calls records every call as "Method arg1 arg2 ...".
+	Calls    []string
+	activity *ActivityLog
+}
+
+func NewFakeClient() *FakeClient {
+	return &FakeClient{
+		Histories: map[string][]types.History{},
+		Notes:     map[string]string{},
+		Metadata:  map[string]string{},
+		Hooks:     map[string]string{},
+		Values:    map[string]string{},
+		Manifests: map[string]string{},
+		Previews:  map[string]Preview{},
+		Templates: map[string]string{},
+		Schemas:   map[string]string{},
+		activity:  NewActivityLog(ActivityLogSize),
+	}
+}
+
+func releaseKey(release, namespace string) string {
+	return namespace + "/" + release
+}
+
+// recordedValues lists the values given to a call: the layered files, the
+// edited file, empty when there is none, and the overrides as flags.
+func recordedValues(files []string, file string, set, setString, setJSON []string) []string {
+	args := append(append([]string{}, files...), file)
+	return append(args, overrideArgs(set, setString, setJSON)...)
+}
+
+func (c *FakeClient) record(method string, args ...string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	call := strings.TrimSpace(method + " " + strings.Join(args, " "))
+	c.Calls = append(c.Calls, call)
+	activity := Activity{Command: strings.Fields(call), Start: time.Now()}
+	if c.Err != nil {
+		activity.ExitCode = 1
+		activity.Stderr = c.Err.Error()
+	}
+	c.activity.Add(activity)
+	return c.Err
+}
+
+func (c *FakeClient) Activity() *ActivityLog {
+	return c.activity
+}
+
+func (c *FakeClient) findRelease(release, namespace string) int {
+	for i, rel := range c.Releases {
+		if rel.Name == release && rel.Namespace == namespace {
+			return i
+		}
+	}
+	return -1
+}
+
+func (c *FakeClient) ListReleases(namespace string) ([]types.Release, error) {
+	if err := c.record("ListReleases", namespace); err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	releases := []types.Release{}
+	for _, rel := range c.Releases {
+		if namespace == "" || rel.Namespace == namespace {
+			releases = append(releases, rel)
+		}
+	}
+	return releases, nil
+}
+
+func (c *FakeClient) History(release, namespace string) ([]types.History, error) {
+	if err := c.record("History", release, namespace); err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.Histories[releaseKey(release, namespace)], nil
+}
+
+func (c *FakeClient) lookup(contents map[string]string, method, release, namespace string) (string, error) {
+	if err := c.record(method, release, namespace); err != nil {
+		return "", err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	content, ok := contents[releaseKey(release, namespace)]
+	if !ok {
+		return "", fmt.Errorf("release: not found")
+	}
+	return content, nil
+}
+
+func (c *FakeClient) GetNotes(release, namespace string) (string, error) {
+	return c.lookup(c.Notes, "GetNotes", release, namespace)
+}
+
+func (c *FakeClient) GetMetadata(release, namespace string) (string, error) {
+	return c.lookup(c.Metadata, "GetMetadata", release, namespace)
+}
+
+func (c *FakeClient) GetHooks(release, namespace string) (string, error) {
+	return c.lookup(c.Hooks, "GetHooks", release, namespace)
+}
+
+func (c *FakeClient) GetValues(release, namespace string) (string, error) {
+	return c.lookup(c.Values, "GetValues", release, namespace)
+}
+
+func (c *FakeClient) GetManifest(release, namespace string) (string, error) {
+	return c.lookup(c.Manifests, "GetManifest", release, namespace)
+}
+
+// GetRevisionValues looks the values of a revision up in Values, as
+// namespace/release@revision.
+func (c *FakeClient) GetRevisionValues(release, namespace string, revision int) (string, error) {
+	return c.lookup(c.Values, "GetRevisionValues", fmt.Sprintf("%s@%d", release, revision), namespace)
+}
+
+// GetRevisionManifest looks the manifest of a revision up in Manifests, as
+// namespace/release@revision.
+func (c *FakeClient) GetRevisionManifest(release, namespace string, revision int) (string, error) {
+	return c.lookup(c.Manifests, "GetRevisionManifest", fmt.Sprintf("%s@%d", release, revision), namespace)
+}
+
+// bumpRevision records a new revision of a release and marks the previous
+// one as superseded.
+func (c *FakeClient) bumpRevision(i int, chart, description string) {
+	rel := c.Releases[i]
+	revision, _ := strconv.Atoi(rel.Revision)
+	revision++
+	key := releaseKey(rel.Name, rel.Namespace)
+	for j := range c.Histories[key] {
+		c.Histories[key][j].Status = "superseded"
+	}
+	c.Histories[key] = append(c.Histories[key], types.History{Revision: revision, Status: "deployed", Chart: chart, Description: description})
+	rel.Revision = strconv.Itoa(revision)
+	rel.Chart = chart
+	rel.Status = "deployed"
+	c.Releases[i] = rel
+}
+
+func (c *FakeClient) Install(opts InstallOptions) error {
+	if err := c.record("Install", append([]string{opts.ReleaseName, opts.Chart, opts.Version, opts.Namespace}, recordedValues(opts.ValuesFiles, opts.ValuesFile, opts.Set, opts.SetString, opts.SetJSON)...)...); err != nil {
+		return err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.findRelease(opts.ReleaseName, opts.Namespace) != -1 {
+		return errors.New("INSTALLATION FAILED: cannot re-use a name that is still in use")
+	}
+	c.Releases = append(c.Releases, types.Release{Name: opts.ReleaseName, Namespace: opts.Namespace, Revision: "0", Chart: opts.Chart})
+	c.bumpRevision(len(c.Releases)-1, opts.Chart, "Install complete")
+	return nil
+}
+
+func (c *FakeClient) Upgrade(opts UpgradeOptions) error {
+	if err := c.record("Upgrade", append([]string{opts.ReleaseName, opts.Chart, opts.Version, opts.Namespace}, recordedValues(opts.ValuesFiles, opts.ValuesFile, opts.Set, opts.SetString, opts.SetJSON)...)...); err != nil {
+		return err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	i := c.findRelease(opts.ReleaseName, opts.Namespace)
+	if i == -1 {
+		return fmt.Errorf("UPGRADE FAILED: %q has no deployed releases", opts.ReleaseName)
+	}
+	c.bumpRevision(i, opts.Chart, "Upgrade complete")
+	return nil
+}
+
+func (c *FakeClient) PreviewInstall(opts InstallOptions) (Preview, error) {
+	if err := c.record("PreviewInstall", append([]string{opts.ReleaseName, opts.Chart, opts.Version, opts.Namespace}, recordedValues(opts.ValuesFiles, opts.ValuesFile, opts.Set, opts.SetString, opts.SetJSON)...)...); err != nil {
+		return Preview{}, err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	preview, ok := c.Previews[releaseKey(opts.ReleaseName, opts.Namespace)]
+	if !ok {
+		return Preview{}, fmt.Errorf("INSTALLATION FAILED: chart %q not found", opts.Chart)
+	}
+	return preview, nil
+}
+
+// Template looks the manifest of a chart up in Templates, keyed by chart.
+func (c *FakeClient) Template(opts InstallOptions) (string, error) {
+	if err := c.record("Template", append([]string{opts.ReleaseName, opts.Chart, opts.Version, opts.Namespace}, recordedValues(opts.ValuesFiles, opts.ValuesFile, opts.Set, opts.SetString, opts.SetJSON)...)...); err != nil {
+		return "", err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	manifest, ok := c.Templates[opts.Chart]
+	if !ok {
+		return "", fmt.Errorf("chart %q not found", opts.Chart)
+	}
+	return manifest, nil
+}
+
+func (c *FakeClient) PreviewUpgrade(opts UpgradeOptions) (Preview, error) {
+	if err := c.record("PreviewUpgrade", append([]string{opts.ReleaseName, opts.Chart, opts.Version, opts.Namespace}, recordedValues(opts.ValuesFiles, opts.ValuesFile, opts.Set, opts.SetString, opts.SetJSON)...)...); err != nil {
+		return Preview{}, err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	preview, ok := c.Previews[releaseKey(opts.ReleaseName, opts.Namespace)]
+	if !ok {
+		return Preview{}, fmt.Errorf("UPGRADE FAILED: %q has no deployed releases", opts.ReleaseName)
+	}
+	return preview, nil
+}
+
+func (c *FakeClient) Rollback(release, revision, namespace string) error {
+	if err := c.record("Rollback", release, revision, namespace); err != nil {
+		return err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	i := c.findRelease(release, namespace)
+	if i == -1 {
+		return errors.New("release: not found")
+	}
+	c.bumpRevision(i, c.Releases[i].Chart, "Rollback to "+revision)
+	return nil
+}
+
+func (c *FakeClient) Uninstall(release, namespace string) error {
+	if err := c.record("Uninstall", release, namespace); err != nil {
+		return err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	i := c.findRelease(release, namespace)
+	if i == -1 {
+		return errors.New("uninstall: Release not loaded: " + release + ": release: not found")
+	}
+	c.Releases = append(c.Releases[:i], c.Releases[i+1:]...)
+	delete(c.Histories, releaseKey(release, namespace))
+	return nil
+}
+
+// ShowValues looks the default values of a chart up in ChartValues, keyed by
+// chart, a chart without any having empty values.
+func (c *FakeClient) ShowValues(chart, version string) (string, error) {
+	if err := c.record("ShowValues", chart, version); err != nil {
+		return "", err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.ChartValues[chart], nil
+}
+
+// ShowSchema looks the schema of a chart up in Schemas, keyed by chart.
+func (c *FakeClient) ShowSchema(chart, version string) (string, error) {
+	if err := c.record("ShowSchema", chart, version); err != nil {
+		return "", err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.Schemas[chart], nil
+}
+
+func (c *FakeClient) SearchRepo(opts SearchOptions) ([]types.Pkg, error) {
+	if err := c.record("SearchRepo", opts.Keyword); err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	pkgs := []types.Pkg{}
+	keyword := strings.Trim(opts.Keyword, "\v")
+	for _, pkg := range c.Packages {
+		if opts.Regexp && pkg.Name != keyword {
+			continue
+		}
+		if !strings.Contains(pkg.Name, keyword) {
+			continue
+		}
+		pkgs = append(pkgs, pkg)
+	}
+	return pkgs, nil
+}
+
+func (c *FakeClient) RepoList() ([]types.Repository, error) {
+	if err := c.record("RepoList"); err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]types.Repository{}, c.Repositories...), nil
+}
+
+func (c *FakeClient) RepoUpdate(names ...string) error {
+	return c.record("RepoUpdate", names...)
+}
+
+func (c *FakeClient) RepoAdd(name, url string) error {
+	if err := c.record("RepoAdd", name, url); err != nil {
+		return err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, repo := range c.Repositories {
+		if repo.Name == name {
+			return fmt.Errorf("repository name (%s) already exists", name)
+		}
+	}
+	c.Repositories = append(c.Repositories, types.Repository{Name: name, URL: url})
+	return nil
+}
+
+func (c *FakeClient) RepoRemove(name string) error {
+	if err := c.record("RepoRemove", name); err != nil {
+		return err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for i, repo := range c.Repositories {
+		if repo.Name == name {
+			c.Repositories = append(c.Repositories[:i], c.Repositories[i+1:]...)
+			return nil
+		}
+	}
+	return fmt.Errorf("no repo named %q found", name)
+}
+
+func (c *FakeClient) PluginList() ([]types.Plugin, error) {
+	if err := c.record("PluginList"); err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]types.Plugin{}, c.Plugins...), nil
+}
+
+func (c *FakeClient) PluginInstall(source string) error {
+	if err := c.record("PluginInstall", source); err != nil {
+		return err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.Plugins = append(c.Plugins, types.Plugin{Name: path.Base(strings.TrimSpace(source))})
+	return nil
+}
+
+func (c *FakeClient) PluginUpdate(name string) error {
+	return c.record("PluginUpdate", name)
+}
+
+func (c *FakeClient) PluginUninstall(name string) error {
+	if err := c.record("PluginUninstall", name); err != nil {
+		return err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for i, plugin := range c.Plugins {
+		if plugin.Name == name {
+			c.Plugins = append(c.Plugins[:i], c.Plugins[i+1:]...)
+			return nil
+		}
+	}
+	return fmt.Errorf("plugin: %s not found", name)
+}
+
+func (c *FakeClient) ListNamespaces() ([]string, error) {
+	if err := c.record("ListNamespaces"); err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]string{}, c.Namespaces...), nil
+}
+
+func (c *FakeClient) KubeContext() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.Context
+}
+
+func (c *FakeClient) SetKubeContext(name string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.Context = name
+}
+
+func (c *FakeClient) KubeConfig() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.Kubeconfig
+}
+
+func (c *FakeClient) SetKubeConfig(path string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.Kubeconfig = path
+}
@@ -0,0 +1,149 @@
+package helm
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/xeipuuv/gojsonschema"
+	"helm.sh/helm/v3/pkg/chartutil"
+	"helm.sh/helm/v3/pkg/getter"
+	"sigs.k8s.io/yaml/goyaml.v3"
+)
+
+// Violation is a value breaking the values.schema.json of a chart.
+type Violation struct {
+	// Path is the JSON path of the value, such as $.image.tag.
+	Path    string
+	Message string
+	// Line is where the value is set in the values file, 0 when it is not.
+	Line int
+}
+
+// ValueSources are the values given to helm for a release: Files merged in
+// order, File, the values edited in a wizard, last, then the overrides.
+type ValueSources struct {
+	Files     []string
+	File      string
+	Set       []string
+	SetString []string
+	SetJSON   []string
+}
+
+// Sources returns the values opts give to helm.
+func (opts InstallOptions) Sources() ValueSources {
+	return ValueSources{Files: opts.ValuesFiles, File: opts.ValuesFile, Set: opts.Set, SetString: opts.SetString, SetJSON: opts.SetJSON}
+}
+
+// Sources returns the values opts give to helm.
+func (opts UpgradeOptions) Sources() ValueSources {
+	return ValueSources{Files: opts.ValuesFiles, File: opts.ValuesFile, Set: opts.Set, SetString: opts.SetString, SetJSON: opts.SetJSON}
+}
+
+// CheckValues validates the values of sources, merged as helm merges them
+// over the default values of the chart, against the chart's
+// values.schema.json. Charts without a schema accept any values.
+func CheckValues(client HelmClient, chart, version string, sources ValueSources) ([]Violation, error) {
+	schema, err := client.ShowSchema(chart, version)
+	if err != nil || schema == "" {
+		return nil, err
+	}
+	defaults, err := client.ShowValues(chart, version)
+	if err != nil {
+		return nil, err
+	}
+	user, err := valueOptions(valueFiles(sources.Files, sources.File), sources.Set, sources.SetString, sources.SetJSON).MergeValues(getter.Providers{})
+	if err != nil {
+		return nil, err
+	}
+	var edited []byte
+	if sources.File != "" {
+		if edited, err = os.ReadFile(sources.File); err != nil {
+			return nil, err
+		}
+	}
+	return validateValues([]byte(schema), []byte(defaults), user, edited)
+}
+
+// ValidateValues validates values, merged over defaults, against schema and
+// lists the violations by path.
+func ValidateValues(schema, defaults, values []byte) ([]Violation, error) {
+	user, err := chartutil.ReadValues(values)
+	if err != nil {
+		return nil, err
+	}
+	return validateValues(schema, defaults, user, values)
+}
+
+// validateValues validates user, merged over defaults, against schema, the
+// violations being looked up in the edited values file.
+func validateValues(schema, defaults []byte, user map[string]interface{}, edited []byte) ([]Violation, error) {
+	base, err := chartutil.ReadValues(defaults)
+	if err != nil {
+		return nil, fmt.Errorf("default values: %w", err)
+	}
+	merged := chartutil.CoalesceTables(user, base)
+	result, err := gojsonschema.Validate(gojsonschema.NewBytesLoader(schema), gojsonschema.NewGoLoader(merged))
+	if err != nil {
+		return nil, fmt.Errorf("values.schema.json: %w", err)
+	}
+	var doc yaml.Node
+	_ = yaml.Unmarshal(edited, &doc)
+	var violations []Violation
+	for _, e := range result.Errors() {
+		var fields []string
+		if e.Field() != "(root)" {
+			fields = strings.Split(e.Field(), ".")
+		}
+		if property, ok := e.Details()["property"].(string); ok && (e.Type() == "required" || e.Type() == "additional_property_not_allowed") {
+			fields = append(fields, property)
+		}
+		violations = append(violations, Violation{Path: jsonPath(fields), Message: e.Description(), Line: valuesLine(&doc, fields)})
+	}
+	sort.SliceStable(violations, func(i, j int) bool { return violations[i].Path < violations[j].Path })
+	return violations, nil
+}
+
+func jsonPath(fields []string) string {
+	path := "$"
+	for _, field := range fields {
+		if _, err := strconv.Atoi(field); err == nil {
+			path += "[" + field + "]"
+			continue
+		}
+		path += "." + field
+	}
+	return path
+}
+
+// valuesLine returns the line setting the deepest of fields found in doc.
+func valuesLine(doc *yaml.Node, fields []string) int {
+	if len(doc.Content) == 0 {
+		return 0
+	}
+	node, line := doc.Content[0], 0
+	for _, field := range fields {
+		var next *yaml.Node
+		switch node.Kind {
+		case yaml.MappingNode:
+			for i := 0; i+1 < len(node.Content); i += 2 {
+				if node.Content[i].Value == field {
+					line, next = node.Content[i].Line, node.Content[i+1]
+					break
+				}
+			}
+		case yaml.SequenceNode:
+			if i, err := strconv.Atoi(field); err == nil && i < len(node.Content) {
+				next = node.Content[i]
+				line = next.Line
+			}
+		}
+		if next == nil {
+			break
+		}
+		node = next
+	}
+	return line
+}
@@ -0,0 +1,89 @@
+package helm
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testSchema = `{
+  "type": "object",
+  "required": ["image"],
+  "properties": {
+    "replicaCount": {"type": "integer"},
+    "image": {
+      "type": "object",
+      "required": ["repository"],
+      "additionalProperties": false,
+      "properties": {
+        "repository": {"type": "string"},
+        "tag": {"type": "string"}
+      }
+    },
+    "ports": {"type": "array", "items": {"type": "integer"}}
+  }
+}`
+
+// TestValidateValues verifies that the violations of the merged values are
+// listed by JSON path, with the line setting them in the values file.
+func TestValidateValues(t *testing.T) {
+	defaults := "replicaCount: 1\nimage:\n  repository: nginx\n"
+	values := "replicaCount: two\nimage:\n  tga: latest\nports:\n  - 80\n  - http\n"
+
+	violations, err := ValidateValues([]byte(testSchema), []byte(defaults), []byte(values))
+
+	require.NoError(t, err)
+	require.Len(t, violations, 3)
+	assert.Equal(t, "$.image.tga", violations[0].Path)
+	assert.Equal(t, 3, violations[0].Line)
+	assert.Contains(t, violations[0].Message, "tga")
+	assert.Equal(t, "$.ports[1]", violations[1].Path)
+	assert.Equal(t, 6, violations[1].Line)
+	assert.Equal(t, "$.replicaCount", violations[2].Path)
+	assert.Equal(t, 1, violations[2].Line)
+}
+
+// TestValidateValuesDefaults verifies that values left to the defaults
+// satisfy the schema.
+func TestValidateValuesDefaults(t *testing.T) {
+	violations, err := ValidateValues([]byte(testSchema), []byte("image:\n  repository: nginx\n"), []byte("replicaCount: 3\n"))
+
+	require.NoError(t, err)
+	assert.Empty(t, violations)
+}
+
+// TestCheckValuesWithoutSchema verifies that charts without schema accept
+// any values.
+func TestCheckValuesWithoutSchema(t *testing.T) {
+	client := NewFakeClient()
+
+	violations, err := CheckValues(client, "bitnami/nginx", "", ValueSources{File: "missing.yaml"})
+
+	assert.NoError(t, err)
+	assert.Empty(t, violations)
+}
+
+// TestCheckValuesLayered verifies that the values files and the overrides
+// given to helm are validated along with the edited values.
+func TestCheckValuesLayered(t *testing.T) {
+	dir := t.TempDir()
+	base, edited := filepath.Join(dir, "base.yaml"), filepath.Join(dir, "values.yaml")
+	require.NoError(t, os.WriteFile(base, []byte("replicaCount: 2\n"), 0644))
+	require.NoError(t, os.WriteFile(edited, []byte("image:\n  tag: latest\n"), 0644))
+	client := NewFakeClient()
+	client.Schemas["bitnami/nginx"] = testSchema
+	client.ChartValues = map[string]string{"bitnami/nginx": "replicaCount: two\nimage:\n  repository: nginx\n"}
+
+	violations, err := CheckValues(client, "bitnami/nginx", "", ValueSources{Files: []string{base}, File: edited})
+	require.NoError(t, err)
+	assert.Empty(t, violations, "the values file should fix the default replicaCount")
+
+	violations, err = CheckValues(client, "bitnami/nginx", "", ValueSources{Files: []string{base}, File: edited, Set: []string{"image.tga=1"}})
+	require.NoError(t, err)
+	require.Len(t, violations, 1)
+	assert.Equal(t, "$.image.tga", violations[0].Path)
+	assert.Equal(t, 1, violations[0].Line, "the edited values set image, not image.tga")
+}
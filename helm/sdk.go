@@ -0,0 +1,773 @@
+package helm
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pidanou/helm-tui/kube"
+	"github.com/pidanou/helm-tui/types"
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chart/loader"
+	"helm.sh/helm/v3/pkg/chartutil"
+	"helm.sh/helm/v3/pkg/cli"
+	"helm.sh/helm/v3/pkg/cli/values"
+	"helm.sh/helm/v3/pkg/getter"
+	"helm.sh/helm/v3/pkg/helmpath"
+	kubefake "helm.sh/helm/v3/pkg/kube/fake"
+	"helm.sh/helm/v3/pkg/plugin"
+	"helm.sh/helm/v3/pkg/plugin/installer"
+	"helm.sh/helm/v3/pkg/registry"
+	"helm.sh/helm/v3/pkg/release"
+	"helm.sh/helm/v3/pkg/releaseutil"
+	"helm.sh/helm/v3/pkg/repo"
+	"helm.sh/helm/v3/pkg/storage"
+	"helm.sh/helm/v3/pkg/storage/driver"
+	"sigs.k8s.io/yaml"
+)
+
+// defaultTimeout is how long helm waits for Kubernetes operations when no
+// --timeout is given.
+const defaultTimeout = 5 * time.Minute
+
+// SDKClient implements HelmClient with the helm Go SDK instead of the helm
+// binary.
+type SDKClient struct {
+	activity *ActivityLog
+	mu       sync.RWMutex
+	settings *cli.EnvSettings
+	// actionConfig returns the configuration used to run actions against a
+	// namespace, "" meaning every namespace.
+	actionConfig func(namespace string) (*action.Configuration, error)
+}
+
+// NewSDKClient returns an SDKClient configured like the helm CLI (kubeconfig,
+// HELM_* environment variables, repository and plugin directories).
+func NewSDKClient() *SDKClient {
+	c := &SDKClient{settings: cli.New(), activity: NewActivityLog(ActivityLogSize)}
+	c.actionConfig = c.clusterConfig
+	return c
+}
+
+// NewMemorySDKClient returns an SDKClient storing releases in memory and
+// printing resources instead of sending them to a cluster. Repositories and
+// plugins are read from settings.
+func NewMemorySDKClient(settings *cli.EnvSettings) *SDKClient {
+	mem := driver.NewMemory()
+	return &SDKClient{
+		settings: settings,
+		activity: NewActivityLog(ActivityLogSize),
+		actionConfig: func(namespace string) (*action.Configuration, error) {
+			mem.SetNamespace(namespace)
+			return &action.Configuration{
+				Releases:     storage.Init(mem),
+				KubeClient:   &kubefake.PrintingKubeClient{Out: io.Discard},
+				Capabilities: chartutil.DefaultCapabilities,
+				Log:          func(string, ...interface{}) {},
+			}, nil
+		},
+	}
+}
+
+func (c *SDKClient) Activity() *ActivityLog {
+	return c.activity
+}
+
+// record logs a call as the helm command it stands for, with the exit code
+// and error output helm would have produced.
+func (c *SDKClient) record(start time.Time, err *error, args ...string) {
+	a := Activity{Command: append([]string{"helm"}, args...), Start: start, Duration: time.Since(start)}
+	if *err != nil {
+		a.ExitCode = 1
+		a.Stderr = "Error: " + (*err).Error()
+	}
+	c.activity.Add(a)
+}
+
+func (c *SDKClient) KubeContext() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.settings.KubeContext
+}
+
+func (c *SDKClient) SetKubeContext(name string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.settings.KubeContext = name
+}
+
+func (c *SDKClient) KubeConfig() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.settings.KubeConfig
+}
+
+func (c *SDKClient) SetKubeConfig(path string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.settings.KubeConfig = path
+}
+
+func (c *SDKClient) ListNamespaces() ([]string, error) {
+	c.mu.RLock()
+	config, err := c.settings.RESTClientGetter().ToRESTConfig()
+	c.mu.RUnlock()
+	if err != nil {
+		return nil, err
+	}
+	return kube.ListNamespaces(config)
+}
+
+func (c *SDKClient) clusterConfig(namespace string) (*action.Configuration, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	cfg := new(action.Configuration)
+	err := cfg.Init(c.settings.RESTClientGetter(), namespace, os.Getenv("HELM_DRIVER"), func(string, ...interface{}) {})
+	if err != nil {
+		return nil, err
+	}
+	cfg.RegistryClient, err = registry.NewClient(
+		registry.ClientOptEnableCache(true),
+		registry.ClientOptWriter(io.Discard),
+		registry.ClientOptCredentialsFile(c.settings.RegistryConfig),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+func chartName(c *chart.Chart) string {
+	if c == nil || c.Metadata == nil {
+		return "MISSING"
+	}
+	return fmt.Sprintf("%s-%s", c.Name(), c.Metadata.Version)
+}
+
+func appVersion(c *chart.Chart) string {
+	if c == nil || c.Metadata == nil {
+		return "MISSING"
+	}
+	return c.AppVersion()
+}
+
+func toRelease(r *release.Release) types.Release {
+	updated := "-"
+	if !r.Info.LastDeployed.IsZero() {
+		updated = r.Info.LastDeployed.String()
+	}
+	return types.Release{
+		Name:       r.Name,
+		Namespace:  r.Namespace,
+		Revision:   strconv.Itoa(r.Version),
+		Updated:    updated,
+		Status:     r.Info.Status.String(),
+		Chart:      chartName(r.Chart),
+		AppVersion: appVersion(r.Chart),
+	}
+}
+
+func toHistory(r *release.Release) types.History {
+	h := types.History{
+		Revision:    r.Version,
+		Status:      r.Info.Status.String(),
+		Chart:       chartName(r.Chart),
+		AppVersion:  appVersion(r.Chart),
+		Description: r.Info.Description,
+	}
+	if !r.Info.LastDeployed.IsZero() {
+		h.Updated = r.Info.LastDeployed.String()
+	}
+	return h
+}
+
+func (c *SDKClient) ListReleases(namespace string) (_ []types.Release, err error) {
+	defer c.record(time.Now(), &err, listArgs(namespace)...)
+	cfg, err := c.actionConfig(namespace)
+	if err != nil {
+		return nil, err
+	}
+	list := action.NewList(cfg)
+	list.AllNamespaces = namespace == ""
+	list.Deployed, list.Failed, list.Pending = true, true, true
+	list.SetStateMask()
+	rls, err := list.Run()
+	if err != nil {
+		return nil, err
+	}
+	releases := make([]types.Release, 0, len(rls))
+	for _, r := range rls {
+		releases = append(releases, toRelease(r))
+	}
+	return releases, nil
+}
+
+func (c *SDKClient) History(name, namespace string) (_ []types.History, err error) {
+	defer c.record(time.Now(), &err, "history", name, "--namespace", namespace)
+	cfg, err := c.actionConfig(namespace)
+	if err != nil {
+		return nil, err
+	}
+	hist := action.NewHistory(cfg)
+	hist.Max = 256
+	rls, err := hist.Run(name)
+	if err != nil {
+		return nil, err
+	}
+	releaseutil.SortByRevision(rls)
+	history := make([]types.History, 0, len(rls))
+	for _, r := range rls {
+		history = append(history, toHistory(r))
+	}
+	if len(history) > hist.Max {
+		history = history[len(history)-hist.Max:]
+	}
+	return history, nil
+}
+
+// get returns a revision of a release, the latest one when revision is 0.
+func (c *SDKClient) get(name, namespace string, revision int) (*release.Release, error) {
+	cfg, err := c.actionConfig(namespace)
+	if err != nil {
+		return nil, err
+	}
+	get := action.NewGet(cfg)
+	get.Version = revision
+	return get.Run(name)
+}
+
+func (c *SDKClient) GetNotes(name, namespace string) (_ string, err error) {
+	defer c.record(time.Now(), &err, "get", "notes", name, "--namespace", namespace)
+	r, err := c.get(name, namespace, 0)
+	if err != nil {
+		return "", err
+	}
+	return r.Info.Notes, nil
+}
+
+func (c *SDKClient) GetMetadata(name, namespace string) (_ string, err error) {
+	defer c.record(time.Now(), &err, "get", "metadata", name, "--namespace", namespace)
+	cfg, err := c.actionConfig(namespace)
+	if err != nil {
+		return "", err
+	}
+	md, err := action.NewGetMetadata(cfg).Run(name)
+	if err != nil {
+		return "", err
+	}
+	var out strings.Builder
+	fmt.Fprintf(&out, "NAME: %v\n", md.Name)
+	fmt.Fprintf(&out, "CHART: %v\n", md.Chart)
+	fmt.Fprintf(&out, "VERSION: %v\n", md.Version)
+	fmt.Fprintf(&out, "APP_VERSION: %v\n", md.AppVersion)
+	fmt.Fprintf(&out, "NAMESPACE: %v\n", md.Namespace)
+	fmt.Fprintf(&out, "REVISION: %v\n", md.Revision)
+	fmt.Fprintf(&out, "STATUS: %v\n", md.Status)
+	fmt.Fprintf(&out, "DEPLOYED_AT: %v\n", md.DeployedAt)
+	return out.String(), nil
+}
+
+func (c *SDKClient) GetHooks(name, namespace string) (_ string, err error) {
+	defer c.record(time.Now(), &err, "get", "hooks", name, "--namespace", namespace)
+	r, err := c.get(name, namespace, 0)
+	if err != nil {
+		return "", err
+	}
+	var out strings.Builder
+	for _, hook := range r.Hooks {
+		fmt.Fprintf(&out, "---\n# Source: %s\n%s\n", hook.Path, hook.Manifest)
+	}
+	return out.String(), nil
+}
+
+func (c *SDKClient) GetValues(name, namespace string) (_ string, err error) {
+	defer c.record(time.Now(), &err, "get", "values", name, "--namespace", namespace)
+	return c.values(name, namespace, 0)
+}
+
+func (c *SDKClient) GetManifest(name, namespace string) (_ string, err error) {
+	defer c.record(time.Now(), &err, "get", "manifest", name, "--namespace", namespace)
+	r, err := c.get(name, namespace, 0)
+	if err != nil {
+		return "", err
+	}
+	return r.Manifest, nil
+}
+
+func (c *SDKClient) GetRevisionValues(name, namespace string, revision int) (_ string, err error) {
+	defer c.record(time.Now(), &err, "get", "values", name, "--namespace", namespace, "--revision", strconv.Itoa(revision))
+	return c.values(name, namespace, revision)
+}
+
+func (c *SDKClient) GetRevisionManifest(name, namespace string, revision int) (_ string, err error) {
+	defer c.record(time.Now(), &err, "get", "manifest", name, "--namespace", namespace, "--revision", strconv.Itoa(revision))
+	r, err := c.get(name, namespace, revision)
+	if err != nil {
+		return "", err
+	}
+	return r.Manifest, nil
+}
+
+// values returns the user-supplied values of a revision of a release, the
+// latest one when revision is 0.
+func (c *SDKClient) values(name, namespace string, revision int) (string, error) {
+	cfg, err := c.actionConfig(namespace)
+	if err != nil {
+		return "", err
+	}
+	getValues := action.NewGetValues(cfg)
+	getValues.Version = revision
+	vals, err := getValues.Run(name)
+	if err != nil {
+		return "", err
+	}
+	out, err := yaml.Marshal(vals)
+	if err != nil {
+		return "", err
+	}
+	return string(out), nil
+}
+
+// loadChart resolves a chart reference (repo/chart, path, URL or OCI
+// reference) and merges the given values.
+func (c *SDKClient) loadChart(pathOptions *action.ChartPathOptions, ref string, valueOpts *values.Options) (*chart.Chart, map[string]interface{}, error) {
+	path, err := pathOptions.LocateChart(ref, c.settings)
+	if err != nil {
+		return nil, nil, err
+	}
+	chrt, err := loader.Load(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	vals, err := valueOpts.MergeValues(getter.All(c.settings))
+	if err != nil {
+		return nil, nil, err
+	}
+	return chrt, vals, nil
+}
+
+// valueOptions returns the values given by values files, merged in order,
+// and key=value overrides.
+func valueOptions(valuesFiles []string, set, setString, setJSON []string) *values.Options {
+	return &values.Options{ValueFiles: valuesFiles, Values: set, StringValues: setString, JSONValues: setJSON}
+}
+
+func (c *SDKClient) Install(opts InstallOptions) (err error) {
+	defer c.record(time.Now(), &err, installArgs(opts)...)
+	_, err = c.install(opts, false)
+	return err
+}
+
+func (c *SDKClient) PreviewInstall(opts InstallOptions) (_ Preview, err error) {
+	defer c.record(time.Now(), &err, append(installArgs(opts), "--dry-run")...)
+	rel, err := c.install(opts, true)
+	if err != nil {
+		return Preview{}, err
+	}
+	return Preview{Manifest: rel.Manifest, Notes: rel.Info.Notes}, nil
+}
+
+func (c *SDKClient) Template(opts InstallOptions) (_ string, err error) {
+	defer c.record(time.Now(), &err, templateArgs(opts)...)
+	install := action.NewInstall(&action.Configuration{Log: func(string, ...interface{}) {}})
+	install.ReleaseName = opts.ReleaseName
+	install.Namespace = opts.Namespace
+	install.Version = opts.Version
+	install.DryRun = true
+	install.ClientOnly = true
+	install.Replace = true
+	chrt, vals, err := c.loadChart(&install.ChartPathOptions, opts.Chart, valueOptions(valueFiles(opts.ValuesFiles, opts.ValuesFile), opts.Set, opts.SetString, opts.SetJSON))
+	if err != nil {
+		return "", err
+	}
+	rel, err := install.Run(chrt, vals)
+	if err != nil {
+		return "", err
+	}
+	return rel.Manifest, nil
+}
+
+func (c *SDKClient) install(opts InstallOptions, dryRun bool) (*release.Release, error) {
+	cfg, err := c.actionConfig(opts.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	install := action.NewInstall(cfg)
+	install.ReleaseName = opts.ReleaseName
+	install.Namespace = opts.Namespace
+	install.CreateNamespace = true
+	install.Version = opts.Version
+	install.DryRun = dryRun
+	chrt, vals, err := c.loadChart(&install.ChartPathOptions, opts.Chart, valueOptions(valueFiles(opts.ValuesFiles, opts.ValuesFile), opts.Set, opts.SetString, opts.SetJSON))
+	if err != nil {
+		return nil, err
+	}
+	return install.Run(chrt, vals)
+}
+
+func (c *SDKClient) Upgrade(opts UpgradeOptions) (err error) {
+	defer c.record(time.Now(), &err, upgradeArgs(opts)...)
+	_, err = c.upgrade(opts, false)
+	return err
+}
+
+func (c *SDKClient) PreviewUpgrade(opts UpgradeOptions) (_ Preview, err error) {
+	defer c.record(time.Now(), &err, append(upgradeArgs(opts), "--dry-run")...)
+	rel, err := c.upgrade(opts, true)
+	if err != nil {
+		return Preview{}, err
+	}
+	return Preview{Manifest: rel.Manifest, Notes: rel.Info.Notes}, nil
+}
+
+func (c *SDKClient) upgrade(opts UpgradeOptions, dryRun bool) (*release.Release, error) {
+	cfg, err := c.actionConfig(opts.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	upgrade := action.NewUpgrade(cfg)
+	upgrade.Namespace = opts.Namespace
+	upgrade.Version = opts.Version
+	upgrade.DryRun = dryRun
+	upgrade.Atomic = opts.Atomic
+	// --atomic implies --wait, as in helm upgrade
+	upgrade.Wait = opts.Wait || opts.Atomic
+	upgrade.WaitForJobs = opts.WaitForJobs
+	upgrade.Timeout = opts.Timeout
+	if upgrade.Timeout == 0 {
+		upgrade.Timeout = defaultTimeout
+	}
+	upgrade.Force = opts.Force
+	upgrade.CleanupOnFail = opts.CleanupOnFail
+	upgrade.ReuseValues = opts.Values == ReuseValues
+	upgrade.ResetValues = opts.Values == ResetValues
+	upgrade.ResetThenReuseValues = opts.Values == ResetThenReuseValues
+	upgrade.MaxHistory = opts.HistoryMax
+	if upgrade.MaxHistory == 0 {
+		upgrade.MaxHistory = c.settings.MaxHistory
+	}
+	upgrade.Description = opts.Description
+	chrt, vals, err := c.loadChart(&upgrade.ChartPathOptions, opts.Chart, valueOptions(valueFiles(opts.ValuesFiles, opts.ValuesFile), opts.Set, opts.SetString, opts.SetJSON))
+	if err != nil {
+		return nil, err
+	}
+	return upgrade.Run(opts.ReleaseName, chrt, vals)
+}
+
+func (c *SDKClient) Rollback(name, revision, namespace string) (err error) {
+	defer c.record(time.Now(), &err, "rollback", name, revision, "--namespace", namespace)
+	cfg, err := c.actionConfig(namespace)
+	if err != nil {
+		return err
+	}
+	rollback := action.NewRollback(cfg)
+	rollback.Version, err = strconv.Atoi(revision)
+	if err != nil {
+		return fmt.Errorf("invalid revision %q: %w", revision, err)
+	}
+	return rollback.Run(name)
+}
+
+func (c *SDKClient) Uninstall(name, namespace string) (err error) {
+	defer c.record(time.Now(), &err, "uninstall", name, "--namespace", namespace)
+	cfg, err := c.actionConfig(namespace)
+	if err != nil {
+		return err
+	}
+	_, err = action.NewUninstall(cfg).Run(name)
+	return err
+}
+
+func (c *SDKClient) ShowValues(chartRef, version string) (_ string, err error) {
+	defer c.record(time.Now(), &err, "show", "values", chartRef, "--version", version)
+	cfg, err := c.actionConfig(c.settings.Namespace())
+	if err != nil {
+		return "", err
+	}
+	show := action.NewShowWithConfig(action.ShowValues, cfg)
+	show.Version = version
+	path, err := show.LocateChart(chartRef, c.settings)
+	if err != nil {
+		return "", err
+	}
+	return show.Run(path)
+}
+
+func (c *SDKClient) ShowSchema(chartRef, version string) (_ string, err error) {
+	defer c.record(time.Now(), &err, "pull", chartRef, "--version", version)
+	chrt, _, err := c.loadChart(&action.ChartPathOptions{Version: version}, chartRef, &values.Options{})
+	if err != nil {
+		return "", err
+	}
+	return string(chrt.Schema), nil
+}
+
+func (c *SDKClient) loadRepoFile() (*repo.File, error) {
+	f, err := repo.LoadFile(c.settings.RepositoryConfig)
+	if errors.Is(err, fs.ErrNotExist) {
+		return repo.NewFile(), nil
+	}
+	return f, err
+}
+
+// SearchRepo searches the cached repository indexes the same way
+// `helm search repo` does: by substring of the chart name and description,
+// or by regular expression on the chart name.
+func (c *SDKClient) SearchRepo(opts SearchOptions) (_ []types.Pkg, err error) {
+	defer c.record(time.Now(), &err, searchArgs(opts)...)
+	f, err := c.loadRepoFile()
+	if err != nil {
+		return nil, err
+	}
+	var re *regexp.Regexp
+	if opts.Regexp {
+		re, err = regexp.Compile(opts.Keyword)
+		if err != nil {
+			return nil, err
+		}
+	}
+	keyword := strings.ToLower(opts.Keyword)
+	pkgs := []types.Pkg{}
+	for _, entry := range f.Repositories {
+		index, err := repo.LoadIndexFile(filepath.Join(c.settings.RepositoryCache, helmpath.CacheIndexFile(entry.Name)))
+		if err != nil {
+			// helm ignores repositories whose index cannot be loaded
+			continue
+		}
+		index.SortEntries()
+		for name, versions := range index.Entries {
+			if len(versions) == 0 {
+				continue
+			}
+			fullName := entry.Name + "/" + name
+			if re != nil {
+				if !re.MatchString(fullName) {
+					continue
+				}
+			} else if !strings.Contains(strings.ToLower(fullName), keyword) && !strings.Contains(strings.ToLower(versions[0].Description), keyword) {
+				continue
+			}
+			if !opts.Versions {
+				versions = versions[:1]
+			}
+			for _, v := range versions {
+				pkgs = append(pkgs, types.Pkg{Name: fullName, Version: v.Version, AppVersion: v.AppVersion, Description: v.Description})
+			}
+		}
+	}
+	return pkgs, nil
+}
+
+func (c *SDKClient) RepoList() (_ []types.Repository, err error) {
+	defer c.record(time.Now(), &err, "repo", "ls")
+	f, err := c.loadRepoFile()
+	if err != nil {
+		return nil, err
+	}
+	if len(f.Repositories) == 0 {
+		return nil, fmt.Errorf("no repositories to show")
+	}
+	repos := make([]types.Repository, 0, len(f.Repositories))
+	for _, entry := range f.Repositories {
+		repos = append(repos, types.Repository{Name: entry.Name, URL: entry.URL})
+	}
+	return repos, nil
+}
+
+func (c *SDKClient) downloadIndex(entry *repo.Entry) error {
+	r, err := repo.NewChartRepository(entry, getter.All(c.settings))
+	if err != nil {
+		return err
+	}
+	r.CachePath = c.settings.RepositoryCache
+	_, err = r.DownloadIndexFile()
+	if err != nil {
+		return fmt.Errorf("looks like %q is not a valid chart repository or cannot be reached: %w", entry.URL, err)
+	}
+	return nil
+}
+
+func (c *SDKClient) RepoUpdate(names ...string) (err error) {
+	defer c.record(time.Now(), &err, append([]string{"repo", "update"}, names...)...)
+	f, err := c.loadRepoFile()
+	if err != nil {
+		return err
+	}
+	if len(f.Repositories) == 0 {
+		return fmt.Errorf("no repositories found. You must add one before updating")
+	}
+	for _, entry := range f.Repositories {
+		if len(names) > 0 && !contains(names, entry.Name) {
+			continue
+		}
+		err = c.downloadIndex(entry)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *SDKClient) RepoAdd(name, url string) (err error) {
+	defer c.record(time.Now(), &err, "repo", "add", name, url)
+	if strings.Contains(name, "/") {
+		return fmt.Errorf("repository name (%s) contains '/', please specify a different name without '/'", name)
+	}
+	f, err := c.loadRepoFile()
+	if err != nil {
+		return err
+	}
+	entry := &repo.Entry{Name: name, URL: url}
+	if f.Has(name) {
+		if *f.Get(name) == *entry {
+			return nil
+		}
+		return fmt.Errorf("repository name (%s) already exists, please specify a different name", name)
+	}
+	err = c.downloadIndex(entry)
+	if err != nil {
+		return err
+	}
+	f.Update(entry)
+	err = os.MkdirAll(filepath.Dir(c.settings.RepositoryConfig), 0755)
+	if err != nil {
+		return err
+	}
+	return f.WriteFile(c.settings.RepositoryConfig, 0600)
+}
+
+func (c *SDKClient) RepoRemove(name string) (err error) {
+	defer c.record(time.Now(), &err, "repo", "remove", name)
+	f, err := c.loadRepoFile()
+	if err != nil {
+		return err
+	}
+	if !f.Remove(name) {
+		return fmt.Errorf("no repo named %q found", name)
+	}
+	err = f.WriteFile(c.settings.RepositoryConfig, 0600)
+	if err != nil {
+		return err
+	}
+	_ = os.Remove(filepath.Join(c.settings.RepositoryCache, helmpath.CacheChartsFile(name)))
+	_ = os.Remove(filepath.Join(c.settings.RepositoryCache, helmpath.CacheIndexFile(name)))
+	return nil
+}
+
+func (c *SDKClient) PluginList() (_ []types.Plugin, err error) {
+	defer c.record(time.Now(), &err, "plugin", "ls")
+	found, err := plugin.FindPlugins(c.settings.PluginsDirectory)
+	if err != nil {
+		return nil, err
+	}
+	plugins := make([]types.Plugin, 0, len(found))
+	for _, p := range found {
+		plugins = append(plugins, types.Plugin{Name: p.Metadata.Name, Version: p.Metadata.Version, Description: p.Metadata.Description})
+	}
+	return plugins, nil
+}
+
+func (c *SDKClient) findPlugin(name string) (*plugin.Plugin, error) {
+	found, err := plugin.FindPlugins(c.settings.PluginsDirectory)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range found {
+		if p.Metadata.Name == name {
+			return p, nil
+		}
+	}
+	return nil, fmt.Errorf("plugin: %s not found", name)
+}
+
+// runPluginHook runs a plugin lifecycle hook the way the helm CLI does.
+func (c *SDKClient) runPluginHook(p *plugin.Plugin, event string) error {
+	hook := p.Metadata.Hooks[event]
+	if hook == "" {
+		return nil
+	}
+	var stderr bytes.Buffer
+	cmd := exec.Command("sh", "-c", hook)
+	plugin.SetupPluginEnv(c.settings, p.Metadata.Name, p.Dir)
+	cmd.Stderr = &stderr
+	err := cmd.Run()
+	if err != nil {
+		return fmt.Errorf("plugin %s hook for %q exited with error: %s", event, p.Metadata.Name, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
+
+func (c *SDKClient) PluginInstall(source string) (err error) {
+	defer c.record(time.Now(), &err, "plugin", "install", source)
+	i, err := installer.NewForSource(strings.TrimSpace(source), "")
+	if err != nil {
+		return err
+	}
+	err = installer.Install(i)
+	if err != nil {
+		return err
+	}
+	p, err := plugin.LoadDir(i.Path())
+	if err != nil {
+		return err
+	}
+	return c.runPluginHook(p, plugin.Install)
+}
+
+func (c *SDKClient) PluginUpdate(name string) (err error) {
+	defer c.record(time.Now(), &err, "plugin", "update", name)
+	p, err := c.findPlugin(name)
+	if err != nil {
+		return err
+	}
+	dir, err := filepath.EvalSymlinks(p.Dir)
+	if err != nil {
+		return err
+	}
+	i, err := installer.FindSource(dir)
+	if err != nil {
+		return err
+	}
+	err = installer.Update(i)
+	if err != nil {
+		return err
+	}
+	p, err = plugin.LoadDir(i.Path())
+	if err != nil {
+		return err
+	}
+	return c.runPluginHook(p, plugin.Update)
+}
+
+func (c *SDKClient) PluginUninstall(name string) (err error) {
+	defer c.record(time.Now(), &err, "plugin", "uninstall", name)
+	p, err := c.findPlugin(strings.TrimSpace(name))
+	if err != nil {
+		return err
+	}
+	err = c.runPluginHook(p, plugin.Delete)
+	if err != nil {
+		return err
+	}
+	return os.RemoveAll(p.Dir)
+}
@@ -0,0 +1,116 @@
+package helm
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"helm.sh/helm/v3/pkg/cli"
+)
+
+// writeTestChart writes a minimal chart to a temporary directory.
+func writeTestChart(t *testing.T, version string) string {
+	dir := filepath.Join(t.TempDir(), "hello")
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "templates"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "Chart.yaml"), []byte("apiVersion: v2\nname: hello\nversion: "+version+"\nappVersion: \"1.0\"\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "values.yaml"), []byte("greeting: hello\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "templates", "configmap.yaml"), []byte("apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: {{ .Release.Name }}\ndata:\n  greeting: {{ .Values.greeting }}\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "templates", "NOTES.txt"), []byte("Installed {{ .Release.Name }}\n"), 0644))
+	return dir
+}
+
+func newTestSDKClient(t *testing.T) *SDKClient {
+	settings := cli.New()
+	settings.RepositoryConfig = filepath.Join(t.TempDir(), "repositories.yaml")
+	settings.RepositoryCache = t.TempDir()
+	settings.PluginsDirectory = t.TempDir()
+	return NewMemorySDKClient(settings)
+}
+
+// TestSDKClientReleaseLifecycle verifies install, upgrade, rollback and
+// uninstall against the in-memory storage driver.
+func TestSDKClientReleaseLifecycle(t *testing.T) {
+	client := newTestSDKClient(t)
+	chart := writeTestChart(t, "0.1.0")
+
+	manifest, err := client.Template(InstallOptions{ReleaseName: "hello", Chart: chart, Namespace: "apps"})
+	require.NoError(t, err)
+	assert.Contains(t, manifest, "kind: ConfigMap")
+	releases, err := client.ListReleases("")
+	require.NoError(t, err)
+	assert.Empty(t, releases, "template should not install the release")
+
+	require.NoError(t, client.Install(InstallOptions{ReleaseName: "hello", Chart: chart, Namespace: "apps"}))
+
+	releases, err = client.ListReleases("")
+	require.NoError(t, err)
+	require.Len(t, releases, 1)
+	assert.Equal(t, "hello", releases[0].Name)
+	assert.Equal(t, "apps", releases[0].Namespace)
+	assert.Equal(t, "1", releases[0].Revision)
+	assert.Equal(t, "deployed", releases[0].Status)
+	assert.Equal(t, "hello-0.1.0", releases[0].Chart)
+
+	notes, err := client.GetNotes("hello", "apps")
+	require.NoError(t, err)
+	assert.Contains(t, notes, "Installed hello")
+
+	manifest, err = client.GetManifest("hello", "apps")
+	require.NoError(t, err)
+	assert.Contains(t, manifest, "greeting: hello")
+
+	valuesFile := filepath.Join(t.TempDir(), "values.yaml")
+	require.NoError(t, os.WriteFile(valuesFile, []byte("greeting: bonjour\n"), 0644))
+	require.NoError(t, client.Upgrade(UpgradeOptions{ReleaseName: "hello", Chart: writeTestChart(t, "0.2.0"), Namespace: "apps", ValuesFile: valuesFile}))
+
+	values, err := client.GetValues("hello", "apps")
+	require.NoError(t, err)
+	assert.Equal(t, "greeting: bonjour\n", values)
+
+	require.NoError(t, client.Rollback("hello", "1", "apps"))
+
+	history, err := client.History("hello", "apps")
+	require.NoError(t, err)
+	require.Len(t, history, 3)
+	assert.Equal(t, 3, history[2].Revision)
+	assert.Equal(t, "hello-0.1.0", history[2].Chart)
+	assert.Equal(t, "superseded", history[1].Status)
+
+	require.NoError(t, client.Uninstall("hello", "apps"))
+	releases, err = client.ListReleases("apps")
+	require.NoError(t, err)
+	assert.Empty(t, releases)
+}
+
+// TestSDKClientErrors verifies that SDK errors are returned to the caller.
+func TestSDKClientErrors(t *testing.T) {
+	client := newTestSDKClient(t)
+
+	_, err := client.History("missing", "default")
+	assert.Error(t, err)
+	assert.Error(t, client.Rollback("missing", "not-a-number", "default"))
+	_, err = client.RepoList()
+	assert.EqualError(t, err, "no repositories to show")
+
+	entries := client.Activity().Entries()
+	assert.Len(t, entries, 3, "Every call should be recorded")
+	assert.Equal(t, "helm history missing --namespace default", entries[0].CommandLine())
+	assert.Equal(t, 1, entries[0].ExitCode)
+	assert.Equal(t, "Error: no repositories to show", entries[2].Stderr)
+}
+
+// TestNewClient verifies backend selection.
+func TestNewClient(t *testing.T) {
+	client, err := NewClient("exec")
+	require.NoError(t, err)
+	assert.IsType(t, &ExecClient{}, client)
+
+	client, err = NewClient("sdk")
+	require.NoError(t, err)
+	assert.IsType(t, &SDKClient{}, client)
+
+	_, err = NewClient("kubectl")
+	assert.Error(t, err)
+}
@@ -1,11 +1,18 @@
 package helpers
 
 import (
+	"fmt"
 	"os"
 	"os/exec"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/pidanou/helm-tui/config"
 	"github.com/pidanou/helm-tui/types"
+	"helm.sh/helm/v3/pkg/chartutil"
 )
 
 func WriteAndOpenFile(content []byte, file string) tea.Cmd {
@@ -20,12 +27,79 @@ func WriteAndOpenFile(content []byte, file string) tea.Cmd {
 		}
 
 	}
-	editor := os.Getenv("EDITOR")
-	if editor == "" {
-		editor = "vim"
-	}
-	c := exec.Command(editor, file)
+	return OpenFile(file)
+}
+
+// OpenFile edits file with the configured editor. Once the editor is closed,
+// the file is checked to be valid YAML.
+func OpenFile(file string) tea.Cmd {
+	return OpenFileAt(file, 0, 0)
+}
+
+// OpenFileAt edits file with the cursor at line and column, when they are
+// not 0 and the editor is known to take them.
+func OpenFileAt(file string, line, column int) tea.Cmd {
+	editor := config.Current.EditorCommand()
+	c := exec.Command(editor[0], append(editor[1:], editorArgs(editor[0], file, line, column)...)...)
 	return tea.ExecProcess(c, func(err error) tea.Msg {
-		return types.EditorFinishedMsg{Err: err}
+		if err != nil {
+			return types.EditorFinishedMsg{Err: err}
+		}
+		return types.EditorFinishedMsg{Invalid: ValidateYAMLFile(file)}
 	})
 }
+
+// editorArgs returns the arguments opening file at line and column with
+// editor, "+line file" being understood by vi, emacs, nano and most others.
+func editorArgs(editor, file string, line, column int) []string {
+	if line == 0 {
+		return []string{file}
+	}
+	position := fmt.Sprintf("%s:%d:%d", file, line, max(column, 1))
+	switch path.Base(editor) {
+	case "code", "code-insiders", "codium", "cursor":
+		return []string{"--goto", position}
+	case "subl", "zed", "hx", "helix":
+		return []string{position}
+	}
+	return []string{"+" + strconv.Itoa(line), file}
+}
+
+// YAMLError locates why a file is not valid YAML.
+type YAMLError struct {
+	File string
+	Line int
+	Msg  string
+}
+
+func (e *YAMLError) Error() string {
+	return fmt.Sprintf("%s: line %d: %s", path.Base(e.File), e.Line, e.Msg)
+}
+
+var (
+	yamlErrorLine = regexp.MustCompile(`^line (\d+): (.*)`)
+	yamlTypeError = regexp.MustCompile(`cannot unmarshal (\w+) into`)
+)
+
+// ValidateYAMLFile checks that file holds a YAML map, parsed as helm parses
+// values files, and returns a *YAMLError otherwise.
+func ValidateYAMLFile(file string) error {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return err
+	}
+	_, err = chartutil.ReadValues(data)
+	if err == nil {
+		return nil
+	}
+	msg := strings.TrimPrefix(err.Error(), "error converting YAML to JSON: ")
+	e := &YAMLError{File: file, Line: 1, Msg: strings.TrimPrefix(msg, "yaml: ")}
+	if match := yamlTypeError.FindStringSubmatch(e.Msg); match != nil {
+		e.Msg = "values must be a map, not " + match[1]
+	}
+	if match := yamlErrorLine.FindStringSubmatch(e.Msg); match != nil {
+		e.Line, _ = strconv.Atoi(match[1])
+		e.Msg = match[2]
+	}
+	return e
+}
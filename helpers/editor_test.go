@@ -0,0 +1,53 @@
+package helpers
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestValidateYAMLFile verifies that values files are parsed as helm parses
+// them and that invalid ones are located by line.
+func TestValidateYAMLFile(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		line    int
+	}{
+		{name: "valid", content: "image:\n  tag: 1.0\n"},
+		{name: "empty", content: ""},
+		{name: "duplicate key", content: "image: a\nimage: b\n"},
+		{name: "bad indentation", content: "image:\n  tag: 1.0\n    pullPolicy: Always\n", line: 3},
+		{name: "tab", content: "image:\n\ttag: 1.0\n", line: 2},
+		{name: "list", content: "- a\n- b\n", line: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file := filepath.Join(t.TempDir(), "values.yaml")
+			require.NoError(t, os.WriteFile(file, []byte(tt.content), 0644))
+
+			err := ValidateYAMLFile(file)
+
+			if tt.line == 0 {
+				assert.NoError(t, err)
+				return
+			}
+			var yamlErr *YAMLError
+			require.ErrorAs(t, err, &yamlErr)
+			assert.Equal(t, tt.line, yamlErr.Line)
+			assert.NotRegexp(t, `^(yaml: )?line \d`, yamlErr.Msg)
+		})
+	}
+}
+
+// TestEditorArgs verifies that the line and column are passed the way each
+// editor takes them.
+func TestEditorArgs(t *testing.T) {
+	assert.Equal(t, []string{"values.yaml"}, editorArgs("vim", "values.yaml", 0, 0))
+	assert.Equal(t, []string{"+3", "values.yaml"}, editorArgs("/usr/bin/nvim", "values.yaml", 3, 5))
+	assert.Equal(t, []string{"--goto", "values.yaml:3:5"}, editorArgs("code", "values.yaml", 3, 5))
+	assert.Equal(t, []string{"values.yaml:3:1"}, editorArgs("hx", "values.yaml", 3, 0))
+}
@@ -0,0 +1,181 @@
+package helpers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"sigs.k8s.io/yaml"
+)
+
+// KeyBindingsFile is the file of ~/.helm-tui key bindings are remapped in.
+const KeyBindingsFile = "keybindings.yaml"
+
+// KeyList is one or more keys, written as a string or a list of strings.
+type KeyList []string
+
+func (k *KeyList) UnmarshalJSON(b []byte) error {
+	var single string
+	if json.Unmarshal(b, &single) == nil {
+		*k = KeyList{single}
+		return nil
+	}
+	var list []string
+	err := json.Unmarshal(b, &list)
+	if err != nil {
+		return errors.New("keys must be a string or a list of strings")
+	}
+	*k = list
+	return nil
+}
+
+type keyScope struct {
+	actions map[string][]*key.Binding
+	// activeWith lists the scopes whose bindings are matched at the same
+	// time as this one.
+	activeWith []string
+}
+
+var keyScopes = map[string]*keyScope{}
+
+// RegisterKeys makes bindings remappable as scope.action. Bindings registered
+// under the same name, such as an action shown in several key maps, are
+// remapped together. The bindings of a scope and of the scopes it is active
+// with must not share keys.
+func RegisterKeys(scope string, bindings map[string]*key.Binding, activeWith ...string) {
+	s, ok := keyScopes[scope]
+	if !ok {
+		s = &keyScope{actions: map[string][]*key.Binding{}}
+		keyScopes[scope] = s
+	}
+	for action, binding := range bindings {
+		s.actions[action] = append(s.actions[action], binding)
+	}
+	for _, other := range activeWith {
+		if !contains(s.activeWith, other) {
+			s.activeWith = append(s.activeWith, other)
+		}
+	}
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+// LoadKeyBindings reads the keys remapped by scope and action, for example:
+//
+//	releases:
+//	  delete: x
+//	  upgrade: [u, ctrl+u]
+//
+// A missing file is not an error.
+func LoadKeyBindings(path string) (map[string]map[string]KeyList, error) {
+	overrides := map[string]map[string]KeyList{}
+	content, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return overrides, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	err = yaml.Unmarshal(content, &overrides)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return overrides, nil
+}
+
+func keyHelp(keys []string) string {
+	names := make([]string, len(keys))
+	for i, k := range keys {
+		if k == " " {
+			k = "space"
+		}
+		names[i] = k
+	}
+	return strings.Join(names, "/")
+}
+
+// ApplyKeyBindings remaps the registered bindings, then checks that no key
+// is bound to two actions active at the same time. It must run before the
+// models copy their key maps.
+func ApplyKeyBindings(overrides map[string]map[string]KeyList) error {
+	var errs []string
+	for scope, actions := range overrides {
+		s, ok := keyScopes[scope]
+		if !ok {
+			errs = append(errs, fmt.Sprintf("unknown key binding scope %q", scope))
+			continue
+		}
+		for action, keys := range actions {
+			bindings, ok := s.actions[action]
+			if !ok {
+				errs = append(errs, fmt.Sprintf("unknown key binding %s.%s", scope, action))
+				continue
+			}
+			for i, k := range keys {
+				if k == "space" {
+					keys[i] = " "
+				}
+			}
+			for _, b := range bindings {
+				if len(keys) == 0 {
+					b.Unbind()
+					continue
+				}
+				b.SetKeys(keys...)
+				b.SetHelp(keyHelp(keys), b.Help().Desc)
+			}
+		}
+	}
+	syncMenuKeys()
+	errs = append(errs, keyConflicts()...)
+	if len(errs) > 0 {
+		sort.Strings(errs)
+		return errors.New(strings.Join(errs, "\n"))
+	}
+	return nil
+}
+
+// keyConflicts lists the keys bound to several actions active at the same
+// time.
+func keyConflicts() []string {
+	var conflicts []string
+	reported := map[string]bool{}
+	for scope, s := range keyScopes {
+		owners := map[string]string{}
+		for _, name := range append([]string{scope}, s.activeWith...) {
+			other, ok := keyScopes[name]
+			if !ok {
+				continue
+			}
+			for action, bindings := range other.actions {
+				for _, k := range bindings[0].Keys() {
+					owner := name + "." + action
+					if previous, ok := owners[k]; ok && previous != owner {
+						pair := []string{previous, owner}
+						sort.Strings(pair)
+						conflict := fmt.Sprintf("key %q is bound to both %s and %s", keyHelp([]string{k}), pair[0], pair[1])
+						if !reported[conflict] {
+							reported[conflict] = true
+							conflicts = append(conflicts, conflict)
+						}
+						continue
+					}
+					owners[k] = owner
+				}
+			}
+		}
+	}
+	return conflicts
+}
@@ -0,0 +1,81 @@
+package helpers
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/stretchr/testify/assert"
+)
+
+// withKeyScopes runs test against a registry holding only the given scopes.
+func withKeyScopes(t *testing.T, register func()) {
+	saved := keyScopes
+	keyScopes = map[string]*keyScope{}
+	t.Cleanup(func() { keyScopes = saved })
+	register()
+}
+
+// TestApplyKeyBindings verifies that remapped keys replace the keys and the help of every binding of an action.
+func TestApplyKeyBindings(t *testing.T) {
+	list := key.NewBinding(key.WithKeys("D"), key.WithHelp("D", "Delete release"))
+	details := key.NewBinding(key.WithKeys("D"), key.WithHelp("D", "Delete release"))
+	refresh := key.NewBinding(key.WithKeys("r"), key.WithHelp("r", "Refresh"))
+	withKeyScopes(t, func() {
+		RegisterKeys("releases", map[string]*key.Binding{"delete": &list, "refresh": &refresh})
+		RegisterKeys("releases", map[string]*key.Binding{"delete": &details})
+	})
+
+	err := ApplyKeyBindings(map[string]map[string]KeyList{"releases": {"delete": {"x", "space"}, "refresh": {}}})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"x", " "}, list.Keys())
+	assert.Equal(t, []string{"x", " "}, details.Keys())
+	assert.Equal(t, key.Help{Key: "x/space", Desc: "Delete release"}, details.Help())
+	assert.False(t, refresh.Enabled(), "An empty list should unbind the action")
+}
+
+// TestApplyKeyBindingsErrors verifies that unknown actions and keys bound twice in active scopes are reported.
+func TestApplyKeyBindingsErrors(t *testing.T) {
+	quit := key.NewBinding(key.WithKeys("ctrl+c"))
+	deleteKey := key.NewBinding(key.WithKeys("D"))
+	upgrade := key.NewBinding(key.WithKeys("u"))
+	rollback := key.NewBinding(key.WithKeys("R"))
+	confirm := key.NewBinding(key.WithKeys("y"))
+	withKeyScopes(t, func() {
+		RegisterKeys("global", map[string]*key.Binding{"quit": &quit})
+		RegisterKeys("releases", map[string]*key.Binding{"delete": &deleteKey, "upgrade": &upgrade, "rollback": &rollback}, "global")
+		RegisterKeys("releases.delete", map[string]*key.Binding{"confirm": &confirm})
+	})
+
+	err := ApplyKeyBindings(map[string]map[string]KeyList{
+		"releases":        {"delete": {"ctrl+c"}, "rollback": {"u"}, "rollout": {"o"}},
+		"releases.delete": {"confirm": {"u"}},
+		"hub":             {"search": {"s"}},
+	})
+
+	assert.EqualError(t, err, `key "ctrl+c" is bound to both global.quit and releases.delete
+key "u" is bound to both releases.rollback and releases.upgrade
+unknown key binding releases.rollout
+unknown key binding scope "hub"`)
+}
+
+// TestLoadKeyBindings verifies that keys can be written as a string or a list, and that a missing file is not an error.
+func TestLoadKeyBindings(t *testing.T) {
+	path := filepath.Join(t.TempDir(), KeyBindingsFile)
+	overrides, err := LoadKeyBindings(path)
+	assert.NoError(t, err)
+	assert.Empty(t, overrides)
+
+	err = os.WriteFile(path, []byte("releases:\n  delete: x\n  upgrade: [u, ctrl+u]\n"), 0644)
+	assert.NoError(t, err)
+	overrides, err = LoadKeyBindings(path)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]map[string]KeyList{"releases": {"delete": {"x"}, "upgrade": {"u", "ctrl+u"}}}, overrides)
+
+	err = os.WriteFile(path, []byte("releases:\n  delete: {key: x}\n"), 0644)
+	assert.NoError(t, err)
+	_, err = LoadKeyBindings(path)
+	assert.Error(t, err)
+}
@@ -8,6 +8,8 @@ import (
 // key.Map. It could also very easily be a map[string]key.Binding.
 type keyMap struct {
 	MenuNext key.Binding
+	PrevTab  key.Binding
+	NextTab  key.Binding
 	Quit     key.Binding
 }
 
@@ -25,9 +27,25 @@ func (k keyMap) FullHelp() [][]key.Binding {
 
 var CommonKeys = keyMap{
 	MenuNext: key.NewBinding(key.WithKeys("[", "]"), key.WithHelp("[/]", "Change panel")),
+	PrevTab:  key.NewBinding(key.WithKeys("["), key.WithHelp("[", "Previous panel")),
+	NextTab:  key.NewBinding(key.WithKeys("]"), key.WithHelp("]", "Next panel")),
 	Quit:     key.NewBinding(key.WithKeys("ctrl+c"), key.WithHelp("ctrl+c", "Quit")),
 }
 
+func init() {
+	RegisterKeys("global", map[string]*key.Binding{
+		"previousTab": &CommonKeys.PrevTab, "nextTab": &CommonKeys.NextTab, "quit": &CommonKeys.Quit,
+	})
+}
+
+// syncMenuKeys shows the remapped previous and next panel keys as the single
+// MenuNext help entry.
+func syncMenuKeys() {
+	keys := append(append([]string{}, CommonKeys.PrevTab.Keys()...), CommonKeys.NextTab.Keys()...)
+	CommonKeys.MenuNext.SetKeys(keys...)
+	CommonKeys.MenuNext.SetHelp(keyHelp(keys), CommonKeys.MenuNext.Help().Desc)
+}
+
 type SuggestionKeyMap struct {
 	AcceptSuggestion key.Binding
 	NextSuggestion   key.Binding
@@ -2,10 +2,11 @@ package helpers
 
 import (
 	"fmt"
-	"os"
+	"io"
 )
 
-var LogFile *os.File
+// LogFile receives the debug logs, set with --log-file.
+var LogFile io.Writer = io.Discard
 
 func Println(args ...any) {
 	args = append(args, "\n")
@@ -0,0 +1,16 @@
+package helpers
+
+import (
+	"errors"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/pidanou/helm-tui/types"
+)
+
+// ErrReadOnly is reported when a change is attempted in read-only mode.
+var ErrReadOnly = errors.New("read-only mode: changes are disabled")
+
+// RefuseReadOnly reports that an action was refused in read-only mode.
+func RefuseReadOnly() tea.Msg {
+	return types.ReadOnlyMsg{Err: ErrReadOnly}
+}
@@ -2,15 +2,20 @@ package hub
 
 import (
 	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/table"
 	"github.com/charmbracelet/bubbles/textinput"
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/pidanou/helm-tui/components"
+	"github.com/pidanou/helm-tui/config"
+	"github.com/pidanou/helm-tui/helm"
+	"github.com/pidanou/helm-tui/helpers"
 	"github.com/pidanou/helm-tui/types"
 )
 
 type HubModel struct {
+	client         helm.HelmClient
 	searchBar      textinput.Model
 	resultTable    table.Model
 	defaultValueVP viewport.Model
@@ -35,9 +40,10 @@ const (
 	defaultValueView
 )
 
-func InitModel() tea.Model {
+func InitModel(client helm.HelmClient) tea.Model {
 	resultTable := components.GenerateTable()
 	m := HubModel{
+		client:         client,
 		searchBar:      textinput.New(),
 		resultTable:    resultTable,
 		defaultValueVP: viewport.New(0, 0),
@@ -50,6 +56,17 @@ func InitModel() tea.Model {
 	return m
 }
 
+// readOnly reports whether changes are refused in the current kube context.
+func (m HubModel) readOnly() bool {
+	return config.Current.ReadOnlyIn(m.client.KubeContext())
+}
+
+// InputFocused reports whether the search bar or the repository name input
+// has focus.
+func (m HubModel) InputFocused() bool {
+	return m.searchBar.Focused() || m.repoAddInput.Focused()
+}
+
 func (m HubModel) Init() tea.Cmd {
 	return nil
 }
@@ -63,31 +80,42 @@ func (m HubModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.height = msg.Height
 		m.searchBar.Width = msg.Width - 5 // -2 for border, -1 for input chevron
 		components.SetTable(&m.resultTable, resultsCols, m.width)
+		components.Sort(&m.resultTable, "hub", resultsCols)
 		m.defaultValueVP.Width = m.width - 2
 		m.repoAddInput.Width = m.width - 5
 	case types.HubSearchResultMsg:
 		m.resultTable.SetRows(msg.Content)
+		components.Sort(&m.resultTable, "hub", resultsCols)
 	case types.HubSearchDefaultValueMsg:
 		m.defaultValueVP.SetContent(msg.Content)
 	case types.AddRepoMsg:
 		m.repoAddInput.SetValue("")
 		m.repoAddInput.Blur()
 	case tea.KeyMsg:
-		switch msg.String() {
-		case "a":
+		if m.readOnly() && !m.InputFocused() && key.Matches(msg, tableKeysHelp.AddRepo) {
+			return m, helpers.RefuseReadOnly
+		}
+		switch {
+		case key.Matches(msg, components.SortKeys.Column, components.SortKeys.Order):
+			if m.resultTable.Focused() && m.view == searchView {
+				components.SortKey(msg, "hub", resultsCols)
+				components.Sort(&m.resultTable, "hub", resultsCols)
+				return m, nil
+			}
+		case key.Matches(msg, tableKeysHelp.AddRepo):
 			if !m.repoAddInput.Focused() && !m.searchBar.Focused() {
 				m.resultTable.Blur()
 				m.searchBar.Blur()
 				cmds = append(cmds, m.repoAddInput.Focus())
 				return m, tea.Batch(cmds...)
 			}
-		case "/":
+		case key.Matches(msg, defaultKeysHelp.Search):
 			if m.view == searchView {
 				m.resultTable.Blur()
 				cmds = append(cmds, m.searchBar.Focus())
 				return m, tea.Batch(cmds...)
 			}
-		case "enter":
+		case key.Matches(msg, defaultKeysHelp.Show):
 			if m.repoAddInput.Focused() {
 				cmds = append(cmds, m.addRepo)
 				return m, tea.Batch(cmds...)
@@ -99,7 +127,7 @@ func (m HubModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				return m, tea.Batch(cmds...)
 			}
 			m.resultTable.Focus()
-		case "v":
+		case key.Matches(msg, tableKeysHelp.Show):
 			if m.resultTable.Focused() {
 				if m.resultTable.SelectedRow() != nil {
 					m.view = defaultValueView
@@ -107,7 +135,7 @@ func (m HubModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				}
 				return m, tea.Batch(cmds...)
 			}
-		case "esc":
+		case key.Matches(msg, defaultValuesKeyHelp.Cancel):
 			m.view = searchView
 			m.repoAddInput.Blur()
 			m.defaultValueVP.GotoTop()
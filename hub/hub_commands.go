@@ -5,10 +5,10 @@ import (
 	"fmt"
 	"io"
 	"net/http"
-	"os/exec"
 
 	"github.com/charmbracelet/bubbles/table"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/pidanou/helm-tui/config"
 	"github.com/pidanou/helm-tui/types"
 )
 
@@ -27,7 +27,7 @@ func (m HubModel) searchHub() tea.Msg {
 	type Response struct {
 		Packages []Package `json:"packages"`
 	}
-	url := fmt.Sprintf("https://artifacthub.io/api/v1/packages/search?offset=0&limit=20&facets=false&ts_query_web=%s&kind=0&deprecated=false&sort=relevance", m.searchBar.Value())
+	url := fmt.Sprintf("%s/api/v1/packages/search?offset=0&limit=20&facets=false&ts_query_web=%s&kind=0&deprecated=false&sort=relevance", config.Current.HubURL, m.searchBar.Value())
 
 	// Create a new HTTP client
 	client := &http.Client{}
@@ -74,7 +74,7 @@ func (m HubModel) searchDefaultValue() tea.Msg {
 		return nil
 	}
 
-	url := fmt.Sprintf("https://artifacthub.io/api/v1/packages/%s/%s/values", m.resultTable.SelectedRow()[0], m.resultTable.SelectedRow()[1])
+	url := fmt.Sprintf("%s/api/v1/packages/%s/%s/values", config.Current.HubURL, m.resultTable.SelectedRow()[0], m.resultTable.SelectedRow()[1])
 
 	// Create a new HTTP client
 	client := &http.Client{}
@@ -108,8 +108,7 @@ func (m HubModel) addRepo() tea.Msg {
 	if m.repoAddInput.Value() == "" || m.resultTable.SelectedRow() == nil {
 		return nil
 	}
-	cmd := exec.Command("helm", "repo", "add", m.repoAddInput.Value(), m.resultTable.SelectedRow()[4])
-	err := cmd.Run()
+	err := m.client.RepoAdd(m.repoAddInput.Value(), m.resultTable.SelectedRow()[4])
 	if err != nil {
 		return types.AddRepoMsg{Err: err}
 	}
@@ -1,6 +1,9 @@
 package hub
 
-import "github.com/charmbracelet/bubbles/key"
+import (
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/pidanou/helm-tui/helpers"
+)
 
 type keyMap struct {
 	AddRepo key.Binding
@@ -42,3 +45,16 @@ var defaultValuesKeyHelp = keyMap{
 	Search: key.NewBinding(key.WithKeys("/"), key.WithHelp("/", "Search")),
 	Cancel: key.NewBinding(key.WithKeys("esc"), key.WithHelp("esc", "Cancel")),
 }
+
+func init() {
+	helpers.RegisterKeys("hub", map[string]*key.Binding{
+		"search": &defaultKeysHelp.Search, "select": &defaultKeysHelp.Show,
+		"showValues": &tableKeysHelp.Show, "addRepo": &tableKeysHelp.AddRepo, "back": &defaultValuesKeyHelp.Cancel,
+	}, "global", "table")
+	helpers.RegisterKeys("hub", map[string]*key.Binding{
+		"search": &tableKeysHelp.Search, "select": &searchKeyHelp.Search,
+	})
+	helpers.RegisterKeys("hub", map[string]*key.Binding{
+		"search": &defaultValuesKeyHelp.Search, "select": &addRepoKeyHelp.Search,
+	})
+}
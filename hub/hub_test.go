@@ -6,12 +6,13 @@ import (
 	"github.com/charmbracelet/bubbles/table"
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/pidanou/helm-tui/helm"
 	"github.com/pidanou/helm-tui/types"
 )
 
 // Test the initialization of the HubModel
 func TestInitModel(t *testing.T) {
-	model := InitModel()
+	model := InitModel(helm.NewFakeClient())
 	if _, ok := model.(HubModel); !ok {
 		t.Error("InitModel did not return a HubModel")
 	}
@@ -19,7 +20,7 @@ func TestInitModel(t *testing.T) {
 
 // Test the Update function with a WindowSizeMsg
 func TestHubModelUpdateWindowSizeMsg(t *testing.T) {
-	model := InitModel().(HubModel)
+	model := InitModel(helm.NewFakeClient()).(HubModel)
 	msg := tea.WindowSizeMsg{Width: 100, Height: 40}
 	updatedModel, _ := model.Update(msg)
 
@@ -50,7 +51,9 @@ func TestSearchHub(t *testing.T) {
 
 // Test the addRepo function with empty input
 func TestAddRepo(t *testing.T) {
+	client := helm.NewFakeClient()
 	model := HubModel{
+		client:       client,
 		repoAddInput: textinput.New(),
 		resultTable: table.New(
 			table.WithColumns([]table.Column{
@@ -81,4 +84,8 @@ func TestAddRepo(t *testing.T) {
 	if _, ok := msg.(types.AddRepoMsg); !ok {
 		t.Error("Expected message of type AddRepoMsg")
 	}
+
+	if len(client.Repositories) != 1 || client.Repositories[0].URL != "https://example.com" {
+		t.Errorf("Expected example-repo to be added with the selected URL, got %v", client.Repositories)
+	}
 }
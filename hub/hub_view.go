@@ -2,6 +2,7 @@ package hub
 
 import (
 	"github.com/charmbracelet/lipgloss"
+	"github.com/pidanou/helm-tui/components"
 	"github.com/pidanou/helm-tui/helpers"
 	"github.com/pidanou/helm-tui/styles"
 )
@@ -26,7 +27,11 @@ func (m HubModel) View() string {
 		helpView = m.help.View(searchKeyHelp)
 	}
 	if m.resultTable.Focused() {
-		helpView = m.help.View(tableKeysHelp)
+		keys := tableKeysHelp
+		if m.readOnly() {
+			keys.AddRepo.SetEnabled(false)
+		}
+		helpView = m.help.View(keys) + helperStyle.Render(" • ") + m.help.View(components.SortKeys)
 	}
 	if m.repoAddInput.Focused() {
 		helpView = m.help.View(addRepoKeyHelp)
@@ -46,7 +51,7 @@ func (m HubModel) renderSearchTableView() string {
 	releasesTopBorder = styles.GenerateTopBorderWithTitle(" Results ", m.resultTable.Width(), styles.Border, styles.InactiveStyle)
 	baseStyle = styles.InactiveStyle.Border(styles.Border, false, true, true)
 	if m.resultTable.Focused() {
-		releasesTopBorder = styles.GenerateTopBorderWithTitle(" Results ", m.resultTable.Width(), styles.Border, styles.ActiveStyle.Foreground(styles.HighlightColor))
+		releasesTopBorder = styles.GenerateTopBorderWithTitle(" Results ", m.resultTable.Width(), styles.Border, styles.ActiveStyle.Foreground(styles.CurrentTheme.Highlight))
 		baseStyle = styles.ActiveStyle.Border(styles.Border, false, true, true)
 	}
 	tableView = baseStyle.Render(tableView)
@@ -0,0 +1,23 @@
+package main
+
+import (
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/pidanou/helm-tui/helpers"
+)
+
+// globalKeyMap holds the bindings available from every tab, on top of
+// helpers.CommonKeys.
+type globalKeyMap struct {
+	Contexts key.Binding
+	Errors   key.Binding
+}
+
+var globalKeys = globalKeyMap{
+	Contexts: key.NewBinding(key.WithKeys("c"), key.WithHelp("c", "Kube contexts")),
+	Errors:   key.NewBinding(key.WithKeys("e"), key.WithHelp("e", "Error details")),
+}
+
+func init() {
+	helpers.RegisterKeys("global", map[string]*key.Binding{"contexts": &globalKeys.Contexts, "errors": &globalKeys.Errors})
+	helpers.RegisterKeys("errors", map[string]*key.Binding{"close": &errorKeys.Close})
+}
@@ -0,0 +1,13 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/pidanou/helm-tui/helpers"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestDefaultKeyBindings verifies that no key is bound to two actions active at the same time.
+func TestDefaultKeyBindings(t *testing.T) {
+	assert.NoError(t, helpers.ApplyKeyBindings(nil))
+}
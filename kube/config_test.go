@@ -0,0 +1,57 @@
+package kube
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const kubeconfig = `apiVersion: v1
+kind: Config
+current-context: staging
+clusters:
+- name: prod-cluster
+  cluster:
+    server: https://prod.example.com
+- name: staging-cluster
+  cluster:
+    server: https://staging.example.com
+users:
+- name: admin
+  user:
+    token: secret
+contexts:
+- name: staging
+  context:
+    cluster: staging-cluster
+    user: admin
+- name: prod
+  context:
+    cluster: prod-cluster
+    user: admin
+    namespace: payments
+`
+
+// TestLoadContexts verifies that contexts and the current context are read from a kubeconfig.
+func TestLoadContexts(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config")
+	require.NoError(t, os.WriteFile(path, []byte(kubeconfig), 0600))
+
+	contexts, current, err := LoadContexts(path)
+
+	require.NoError(t, err)
+	assert.Equal(t, "staging", current)
+	assert.Equal(t, []Context{
+		{Name: "prod", Cluster: "prod-cluster", User: "admin", Namespace: "payments"},
+		{Name: "staging", Cluster: "staging-cluster", User: "admin"},
+	}, contexts)
+}
+
+// TestLoadContextsMissingFile verifies that an explicit kubeconfig must exist.
+func TestLoadContextsMissingFile(t *testing.T) {
+	_, _, err := LoadContexts(filepath.Join(t.TempDir(), "missing"))
+	assert.Error(t, err)
+}
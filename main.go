@@ -1,34 +1,154 @@
 package main
 
 import (
+	"flag"
 	"fmt"
+	"io"
 	"log"
 	"os"
+	"path"
+	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/pidanou/helm-tui/config"
+	"github.com/pidanou/helm-tui/helm"
 	"github.com/pidanou/helm-tui/helpers"
+	"github.com/pidanou/helm-tui/kube"
+	"github.com/pidanou/helm-tui/styles"
 )
 
+func fatal(args ...any) {
+	fmt.Println(append([]any{"fatal:"}, args...)...)
+	os.Exit(1)
+}
+
+// contextNamespace is the namespace helm falls back to without -n: the one
+// of the kube context, else "default".
+func contextNamespace(kubeconfig, context string) string {
+	contexts, current, err := kube.LoadContexts(kubeconfig)
+	if err != nil {
+		return "default"
+	}
+	if context == "" {
+		context = current
+	}
+	for _, c := range contexts {
+		if c.Name == context && c.Namespace != "" {
+			return c.Namespace
+		}
+	}
+	return "default"
+}
+
 func main() {
-	f, err := tea.LogToFile("debug.log", "debug")
+	backend := flag.String("backend", "exec", "helm backend: exec (helm binary on PATH) or sdk (built-in helm SDK)")
+	persistActivity := flag.Bool("activity-log", false, "append the executed helm commands to ~/.helm-tui/"+activityFile)
+	namespace := flag.String("namespace", "", "list the releases of this namespace and install into it")
+	flag.StringVar(namespace, "n", "", "shorthand for --namespace")
+	kubeContext := flag.String("kube-context", "", "kubeconfig context to use")
+	kubeConfig := flag.String("kubeconfig", "", "path to the kubeconfig file")
+	tab := flag.String("tab", "", "tab shown at startup: "+strings.Join(config.Tabs, ", "))
+	release := flag.String("release", "", "open the details of a release, given as namespace/name")
+	readOnly := flag.Bool("read-only", false, "refuse the actions changing releases, repositories or plugins")
+	logFile := flag.String("log-file", "", "append debug logs to this file")
+	helmNamespace := flag.String("helm-namespace", "", "namespace exported by helm to plugins, used when it is not the one of the kube context")
+	flag.Parse()
+
+	configPath, err := config.Path()
+	if err != nil {
+		fatal(err)
+	}
+	config.Current, err = config.Load(configPath)
+	if err != nil {
+		fatal(err)
+	}
+	if *tab != "" {
+		config.Current.StartTab = *tab
+		if config.Current.TabIndex() == -1 {
+			fatal(fmt.Sprintf("unknown tab %q, expected one of %s", *tab, strings.Join(config.Tabs, ", ")))
+		}
+	}
+	if *namespace == "" && *helmNamespace != "" && *helmNamespace != contextNamespace(*kubeConfig, *kubeContext) {
+		// helm always exports a namespace to plugins: only one differing from
+		// its fallback was given with -n
+		*namespace = *helmNamespace
+	}
+	if *release != "" {
+		releaseNamespace, name, ok := strings.Cut(*release, "/")
+		if !ok || releaseNamespace == "" || name == "" {
+			fatal(fmt.Sprintf("invalid release %q, expected namespace/name", *release))
+		}
+		if *namespace == "" {
+			*namespace = releaseNamespace
+		}
+		if *tab == "" {
+			config.Current.StartTab = config.Tabs[0]
+		}
+	}
+	if *namespace != "" {
+		config.Current.DefaultNamespace = *namespace
+	}
+	if *kubeContext != "" {
+		config.Current.KubeContext = *kubeContext
+	}
+	if *readOnly {
+		config.Current.ReadOnly = true
+	}
+
+	err = styles.SetTheme(config.Current.Theme)
+	if err != nil {
+		fatal(err)
+	}
+
+	configDir, err := config.Dir()
+	if err != nil {
+		fatal(err)
+	}
+	keyBindings, err := helpers.LoadKeyBindings(path.Join(configDir, helpers.KeyBindingsFile))
+	if err == nil {
+		err = helpers.ApplyKeyBindings(keyBindings)
+	}
 	if err != nil {
-		fmt.Println("fatal:", err)
-		os.Exit(1)
+		fatal("invalid key bindings:\n" + err.Error())
 	}
-	helpers.LogFile = f
-	defer f.Close()
-	defer os.Truncate("debug.log", 0)
 
-	var tabs []string
+	client, err := helm.NewClient(*backend)
+	if err != nil {
+		fatal(err)
+	}
+	if execClient, ok := client.(*helm.ExecClient); ok {
+		execClient.Binary = config.Current.HelmBinary
+	}
+	if config.Current.KubeContext != "" {
+		client.SetKubeContext(config.Current.KubeContext)
+	}
+	if *kubeConfig != "" {
+		client.SetKubeConfig(*kubeConfig)
+	}
+	if client.KubeContext() == "" {
+		// pin the current context so that read-only contexts stay protected
+		// if the kubeconfig changes during the session
+		_, current, err := kube.LoadContexts(client.KubeConfig())
+		if err == nil {
+			client.SetKubeContext(current)
+		}
+	}
 
-	// Iterate over the map and collect the values
-	for _, value := range tabLabels {
-		tabs = append(tabs, value)
+	log.SetOutput(io.Discard)
+	if *logFile != "" {
+		f, err := tea.LogToFile(*logFile, "debug")
+		if err != nil {
+			fatal(err)
+		}
+		helpers.LogFile = f
+		defer f.Close()
 	}
 
-	p := tea.NewProgram(newModel(tabs), tea.WithAltScreen())
+	m := newModel(tabLabels, client, startOptions{namespace: *namespace, release: *release})
+	m.persistActivity = *persistActivity || config.Current.ActivityLog
+	p := tea.NewProgram(m, tea.WithAltScreen())
 
 	if _, err := p.Run(); err != nil {
-		log.Fatal(err)
+		fatal(err)
 	}
 }
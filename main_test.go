@@ -0,0 +1,32 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestContextNamespace verifies that the namespace helm falls back to is the
+// one of the kube context, else default.
+func TestContextNamespace(t *testing.T) {
+	kubeconfig := filepath.Join(t.TempDir(), "config")
+	err := os.WriteFile(kubeconfig, []byte(`apiVersion: v1
+kind: Config
+current-context: dev
+contexts:
+- name: dev
+  context:
+    cluster: dev
+    namespace: payments
+- name: prod
+  context:
+    cluster: prod
+`), 0o600)
+	assert.NoError(t, err)
+
+	assert.Equal(t, "payments", contextNamespace(kubeconfig, ""))
+	assert.Equal(t, "default", contextNamespace(kubeconfig, "prod"))
+	assert.Equal(t, "default", contextNamespace(filepath.Join(t.TempDir(), "missing"), ""))
+}
@@ -7,6 +7,9 @@ import (
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/pidanou/helm-tui/components"
+	"github.com/pidanou/helm-tui/config"
+	"github.com/pidanou/helm-tui/helm"
+	"github.com/pidanou/helm-tui/helpers"
 	"github.com/pidanou/helm-tui/types"
 )
 
@@ -17,6 +20,7 @@ var pluginsCols = []components.ColumnDefinition{
 }
 
 type PluginsModel struct {
+	client             helm.HelmClient
 	pluginsTable       table.Model
 	installPluginInput textinput.Model
 	help               help.Model
@@ -25,17 +29,27 @@ type PluginsModel struct {
 	height             int
 }
 
-func InitModel() PluginsModel {
+func InitModel(client helm.HelmClient) PluginsModel {
 	table := components.GenerateTable()
 	input := textinput.New()
 	input.Placeholder = "Enter plugin path/url"
-	return PluginsModel{pluginsTable: table, help: help.New(), keys: overviewKeys, installPluginInput: input}
+	return PluginsModel{client: client, pluginsTable: table, help: help.New(), keys: overviewKeys, installPluginInput: input}
 }
 
 func (m PluginsModel) Init() tea.Cmd {
 	return m.list
 }
 
+// readOnly reports whether changes are refused in the current kube context.
+func (m PluginsModel) readOnly() bool {
+	return config.Current.ReadOnlyIn(m.client.KubeContext())
+}
+
+// InputFocused reports whether the plugin source input has focus.
+func (m PluginsModel) InputFocused() bool {
+	return m.installPluginInput.Focused()
+}
+
 func (m PluginsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmd tea.Cmd
 	var cmds []tea.Cmd
@@ -45,16 +59,31 @@ func (m PluginsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.width = msg.Width
 		m.height = msg.Height
 		components.SetTable(&m.pluginsTable, pluginsCols, m.width)
+		components.Sort(&m.pluginsTable, "plugins", pluginsCols)
 	case types.PluginsListMsg:
 		m.pluginsTable.SetRows(msg.Content)
+		components.Sort(&m.pluginsTable, "plugins", pluginsCols)
 	case types.PluginInstallMsg:
+		if msg.Err != nil {
+			// keep the source so it can be fixed
+			return m, nil
+		}
 		m.installPluginInput.Blur()
 		m.installPluginInput.SetValue("")
 		return m, m.list
 	case types.PluginUninstallMsg:
 		return m, m.list
 	case tea.KeyMsg:
+		if m.readOnly() && !m.installPluginInput.Focused() && key.Matches(msg, m.keys.Install, m.keys.Uninstall) {
+			return m, helpers.RefuseReadOnly
+		}
 		switch {
+		case key.Matches(msg, components.SortKeys.Column, components.SortKeys.Order):
+			if !m.installPluginInput.Focused() {
+				components.SortKey(msg, "plugins", pluginsCols)
+				components.Sort(&m.pluginsTable, "plugins", pluginsCols)
+				return m, nil
+			}
 		case key.Matches(msg, m.keys.Install):
 			cmds = append(cmds, m.installPluginInput.Focus())
 			return m, tea.Batch(cmds...)
@@ -71,7 +100,7 @@ func (m PluginsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, tea.Batch(cmds...)
 		case key.Matches(msg, m.keys.Refresh):
 			return m, m.list
-		case msg.String() == "enter":
+		case key.Matches(msg, m.keys.Submit):
 			if m.installPluginInput.Focused() {
 				return m, m.install
 			}
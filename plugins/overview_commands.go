@@ -1,10 +1,7 @@
 package plugins
 
 import (
-	"bytes"
 	"errors"
-	"os/exec"
-	"strings"
 
 	"github.com/charmbracelet/bubbles/table"
 	tea "github.com/charmbracelet/bubbletea"
@@ -12,26 +9,15 @@ import (
 )
 
 func (m PluginsModel) list() tea.Msg {
-	var stdout bytes.Buffer
 	var rows = []table.Row{}
 
-	// Create the command
-	cmd := exec.Command("helm", "plugin", "ls")
-	cmd.Stdout = &stdout
-	err := cmd.Run()
+	plugins, err := m.client.PluginList()
 	if err != nil {
-		return types.ListReleasesMsg{Err: err}
+		return types.PluginsListMsg{Err: err}
 	}
 
-	lines := strings.Split(stdout.String(), "\n")
-	lines = lines[1 : len(lines)-1]
-
-	for _, line := range lines {
-		fields := strings.Fields(line)
-		name := fields[0]
-		version := fields[1]
-		description := strings.Join(fields[2:], " ")
-		row := []string{name, version, description}
+	for _, plugin := range plugins {
+		row := []string{plugin.Name, plugin.Version, plugin.Description}
 		rows = append(rows, row)
 	}
 	return types.PluginsListMsg{Content: rows}
@@ -42,10 +28,9 @@ func (m PluginsModel) install() tea.Msg {
 	if pluginName == "" {
 		return types.PluginInstallMsg{Err: errors.New("No plugin")}
 	}
-	cmd := exec.Command("helm", "plugin", "install", strings.TrimSpace(pluginName))
-	err := cmd.Run()
+	err := m.client.PluginInstall(pluginName)
 	if err != nil {
-		return types.PluginInstallMsg{Err: errors.New("Cannot install plugin")}
+		return types.PluginInstallMsg{Err: err}
 	}
 	return types.PluginInstallMsg{Err: nil}
 }
@@ -55,11 +40,10 @@ func (m PluginsModel) update() tea.Msg {
 		return types.PluginUpdateMsg{Err: errors.New("No plugin selected")}
 	}
 	pluginName := m.pluginsTable.SelectedRow()[0]
-	cmd := exec.Command("helm", "plugin", "update", pluginName)
-	err := cmd.Run()
+	err := m.client.PluginUpdate(pluginName)
 
 	if err != nil {
-		return types.PluginUpdateMsg{Err: errors.New("Cannot update plugin")}
+		return types.PluginUpdateMsg{Err: err}
 	}
 	return types.PluginUpdateMsg{Err: nil}
 }
@@ -69,10 +53,9 @@ func (m PluginsModel) uninstall() tea.Msg {
 		return types.PluginUninstallMsg{Err: errors.New("No plugin selected")}
 	}
 	pluginName := m.pluginsTable.SelectedRow()[0]
-	cmd := exec.Command("helm", "plugin", "uninstall", strings.TrimSpace(pluginName))
-	err := cmd.Run()
+	err := m.client.PluginUninstall(pluginName)
 	if err != nil {
-		return types.PluginUninstallMsg{Err: errors.New("Cannot update plugin")}
+		return types.PluginUninstallMsg{Err: err}
 	}
 	return types.PluginUninstallMsg{Err: nil}
 }
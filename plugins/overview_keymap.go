@@ -1,6 +1,9 @@
 package plugins
 
-import "github.com/charmbracelet/bubbles/key"
+import (
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/pidanou/helm-tui/helpers"
+)
 
 type keyMap struct {
 	Install   key.Binding
@@ -8,6 +11,7 @@ type keyMap struct {
 	Uninstall key.Binding
 	Cancel    key.Binding
 	Refresh   key.Binding
+	Submit    key.Binding
 }
 
 var overviewKeys = keyMap{
@@ -16,6 +20,21 @@ var overviewKeys = keyMap{
 	Update:    key.NewBinding(key.WithKeys("u"), key.WithHelp("u", "Update")),
 	Cancel:    key.NewBinding(key.WithKeys("esc"), key.WithHelp("esc", "Cancel")),
 	Refresh:   key.NewBinding(key.WithKeys("r"), key.WithHelp("r", "Refresh")),
+	Submit:    key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "Install")),
+}
+
+func init() {
+	helpers.RegisterKeys("plugins", map[string]*key.Binding{
+		"install": &overviewKeys.Install, "update": &overviewKeys.Update, "uninstall": &overviewKeys.Uninstall,
+		"cancel": &overviewKeys.Cancel, "refresh": &overviewKeys.Refresh, "submit": &overviewKeys.Submit,
+	}, "global", "table")
+}
+
+// withoutChanges hides the bindings refused in read-only mode.
+func (k keyMap) withoutChanges() keyMap {
+	k.Install.SetEnabled(false)
+	k.Uninstall.SetEnabled(false)
+	return k
 }
 
 func (k keyMap) ShortHelp() []key.Binding {
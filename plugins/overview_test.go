@@ -1 +1,59 @@
 package plugins
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/pidanou/helm-tui/config"
+	"github.com/pidanou/helm-tui/helm"
+	"github.com/pidanou/helm-tui/helpers"
+	"github.com/pidanou/helm-tui/types"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestListPlugins verifies that installed plugins fill the table.
+func TestListPlugins(t *testing.T) {
+	client := helm.NewFakeClient()
+	client.Plugins = []types.Plugin{{Name: "diff", Version: "3.9.11", Description: "Preview helm upgrade changes as a diff"}}
+	m := InitModel(client)
+	updated, _ := m.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+
+	updated, _ = updated.Update(m.list())
+
+	rows := updated.(PluginsModel).pluginsTable.Rows()
+	assert.Len(t, rows, 1)
+	assert.Equal(t, "diff", rows[0][0])
+}
+
+// TestInstallPlugin verifies that entering a plugin url installs it.
+func TestInstallPlugin(t *testing.T) {
+	client := helm.NewFakeClient()
+	m := InitModel(client)
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("i")})
+	m = updated.(PluginsModel)
+	m.installPluginInput.SetValue("https://github.com/databus23/helm-diff")
+
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+
+	assert.Equal(t, types.PluginInstallMsg{Err: nil}, cmd())
+	assert.Equal(t, []string{"PluginInstall https://github.com/databus23/helm-diff"}, client.Calls)
+}
+
+// TestReadOnlyRefusesInstall verifies that plugins cannot be installed or uninstalled in a read-only context.
+func TestReadOnlyRefusesInstall(t *testing.T) {
+	config.Current.ReadOnlyContexts = []string{"production"}
+	t.Cleanup(func() { config.Current = config.Default() })
+	client := helm.NewFakeClient()
+	client.Context = "production"
+	m := InitModel(client)
+
+	for _, k := range []string{"i", "U"} {
+		updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k)})
+
+		assert.False(t, updated.(PluginsModel).InputFocused())
+		assert.Equal(t, types.ReadOnlyMsg{Err: helpers.ErrReadOnly}, cmd())
+	}
+	assert.Empty(t, client.Calls)
+	assert.NotContains(t, m.View(), "Uninstall")
+}
@@ -13,7 +13,11 @@ func (m PluginsModel) View() string {
 		remainingHeight -= 3
 	}
 	helperStyle := m.help.Styles.ShortSeparator
-	helpView := m.help.View(m.keys) + helperStyle.Render(" • ") + m.help.View(helpers.CommonKeys)
+	keys := m.keys
+	if m.readOnly() {
+		keys = keys.withoutChanges()
+	}
+	helpView := m.help.View(keys) + helperStyle.Render(" • ") + m.help.View(components.SortKeys) + helperStyle.Render(" • ") + m.help.View(helpers.CommonKeys)
 	view := components.RenderTable(m.pluginsTable, remainingHeight-3, m.width-2)
 	m.installPluginInput.Width = m.width - 5
 	if m.installPluginInput.Focused() {
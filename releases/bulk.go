@@ -0,0 +1,302 @@
+package releases
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/pidanou/helm-tui/helm"
+	"github.com/pidanou/helm-tui/helpers"
+	"github.com/pidanou/helm-tui/styles"
+	"github.com/pidanou/helm-tui/types"
+)
+
+type bulkAction int
+
+const (
+	bulkUninstall bulkAction = iota
+	bulkRollback
+	bulkUpgrade
+	bulkExport
+)
+
+var bulkActionLabels = []string{"Uninstall", "Roll back", "Upgrade to latest", "Export values of"}
+
+// bulkConcurrency bounds the helm commands run at once by a bulk action.
+const bulkConcurrency = 4
+
+// exportsDir holds, per namespace, the values exported by bulk actions.
+// Namespace names cannot start with a dot, so it never holds the values of
+// a wizard.
+const exportsDir = ".exports"
+
+type bulkJob struct {
+	release table.Row
+	done    bool
+	result  string
+	err     error
+}
+
+// bulkRun is an action run on several releases, waiting for confirmation
+// until started.
+type bulkRun struct {
+	action  bulkAction
+	jobs    []bulkJob
+	started bool
+	// tag tells the results of this run from those of a previous one.
+	tag int
+}
+
+// bulkResultMsg reports the outcome of the job at index of a bulk run.
+type bulkResultMsg struct {
+	tag    int
+	index  int
+	result string
+	err    error
+}
+
+func (b bulkRun) active() bool {
+	return len(b.jobs) > 0
+}
+
+func (b bulkRun) finished() bool {
+	for _, job := range b.jobs {
+		if !job.done {
+			return false
+		}
+	}
+	return true
+}
+
+func (b bulkRun) failures() int {
+	failed := 0
+	for _, job := range b.jobs {
+		if job.err != nil {
+			failed++
+		}
+	}
+	return failed
+}
+
+// targets returns the marked releases, or the selected one when none is
+// marked.
+func (m Model) targets() []table.Row {
+	var rows []table.Row
+	for _, row := range m.releaseTable.Rows() {
+		if m.marked[releaseKey(row)] {
+			rows = append(rows, row)
+		}
+	}
+	if len(rows) == 0 && m.releaseTable.SelectedRow() != nil {
+		rows = append(rows, m.releaseTable.SelectedRow())
+	}
+	return rows
+}
+
+// markedCount returns the number of marked releases left by the filter.
+func (m Model) markedCount() int {
+	count := 0
+	for _, row := range m.releaseTable.Rows() {
+		if m.marked[releaseKey(row)] {
+			count++
+		}
+	}
+	return count
+}
+
+// prepareBulk asks to confirm action on the targets, or starts it right away
+// when it changes nothing in the cluster.
+func (m *Model) prepareBulk(action bulkAction) tea.Cmd {
+	targets := m.targets()
+	if len(targets) == 0 {
+		return nil
+	}
+	m.bulk = bulkRun{action: action, tag: m.bulk.tag + 1}
+	for _, row := range targets {
+		m.bulk.jobs = append(m.bulk.jobs, bulkJob{release: row})
+	}
+	if action == bulkExport {
+		return m.startBulk()
+	}
+	return nil
+}
+
+// startBulk runs the jobs of the bulk run, at most bulkConcurrency at once.
+func (m *Model) startBulk() tea.Cmd {
+	m.bulk.started = true
+	m.marked = nil
+	sem := make(chan struct{}, bulkConcurrency)
+	client, action, tag := m.client, m.bulk.action, m.bulk.tag
+	var cmds []tea.Cmd
+	for i, job := range m.bulk.jobs {
+		cmds = append(cmds, func() tea.Msg {
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			result, err := runBulkJob(client, action, job.release)
+			return bulkResultMsg{tag: tag, index: i, result: result, err: err}
+		})
+	}
+	return tea.Batch(cmds...)
+}
+
+func runBulkJob(client helm.HelmClient, action bulkAction, row table.Row) (string, error) {
+	name, namespace := row[0], row[1]
+	switch action {
+	case bulkUninstall:
+		return "uninstalled", client.Uninstall(name, namespace)
+	case bulkRollback:
+		revision, err := strconv.Atoi(row[2])
+		if err != nil || revision <= 1 {
+			return "", errors.New("no previous revision")
+		}
+		previous := strconv.Itoa(revision - 1)
+		return "rolled back to revision " + previous, client.Rollback(name, previous, namespace)
+	case bulkUpgrade:
+		return upgradeToLatest(client, row)
+	case bulkExport:
+		values, err := client.GetValues(name, namespace)
+		if err != nil {
+			return "", err
+		}
+		file := filepath.Join(helpers.UserDir, exportsDir, namespace, name+".yaml")
+		if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+			return "", err
+		}
+		return file, os.WriteFile(file, []byte(values), 0644)
+	}
+	return "", nil
+}
+
+// chartVersion splits the chart column of helm list, such as
+// nginx-ingress-4.10.1, into the chart name and version.
+var chartVersion = regexp.MustCompile(`^(.+)-(v?\d+\.\d+\.\d+.*)$`)
+
+// upgradeToLatest upgrades a release to the latest version of its chart found
+// in the local repositories, keeping its values.
+func upgradeToLatest(client helm.HelmClient, row table.Row) (string, error) {
+	name, namespace := row[0], row[1]
+	parts := chartVersion.FindStringSubmatch(row[5])
+	if parts == nil {
+		return "", fmt.Errorf("cannot tell the chart of %q", row[5])
+	}
+	pkgs, err := client.SearchRepo(helm.SearchOptions{Keyword: parts[1]})
+	if err != nil {
+		return "", err
+	}
+	// helm does not record the repository of a release, so the chart must be
+	// found in a single one
+	var matches []types.Pkg
+	for _, pkg := range pkgs {
+		if pkg.Name == parts[1] || strings.HasSuffix(pkg.Name, "/"+parts[1]) {
+			matches = append(matches, pkg)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return "", fmt.Errorf("chart %s not found in the local repositories", parts[1])
+	case 1:
+	default:
+		names := make([]string, len(matches))
+		for i, pkg := range matches {
+			names[i] = pkg.Name
+		}
+		return "", fmt.Errorf("chart %s found in several repositories (%s), upgrade the release from the wizard", parts[1], strings.Join(names, ", "))
+	}
+	chart, version := matches[0].Name, matches[0].Version
+	if version == parts[2] {
+		return "already at " + version, nil
+	}
+	values, err := client.GetValues(name, namespace)
+	if err != nil {
+		return "", err
+	}
+	// not the folder of the wizards, which may be editing the same release
+	folder, err := os.MkdirTemp("", "helm-tui-bulk-*")
+	if err != nil {
+		return "", err
+	}
+	defer os.RemoveAll(folder)
+	file := filepath.Join(folder, "values.yaml")
+	if err := os.WriteFile(file, []byte(values), 0644); err != nil {
+		return "", err
+	}
+	err = client.Upgrade(helm.UpgradeOptions{ReleaseName: name, Chart: chart, Version: version, Namespace: namespace, ValuesFile: file})
+	return "upgraded to " + version, err
+}
+
+// visualRange returns the keys of the releases between the start of the
+// visual range and the cursor.
+func (m Model) visualRange() map[string]bool {
+	keys := map[string]bool{}
+	if m.visualStart < 0 {
+		return keys
+	}
+	from, to := min(m.visualStart, m.releaseTable.Cursor()), max(m.visualStart, m.releaseTable.Cursor())
+	rows := m.releaseTable.Rows()
+	for i := from; i <= to && i < len(rows); i++ {
+		keys[releaseKey(rows[i])] = true
+	}
+	return keys
+}
+
+// rowStyle highlights the marked releases, then those changed by the last
+// listing, and otherwise colors the statuses. Statuses always get their
+// glyph.
+func (m Model) rowStyle() func(table.Row, int) (lipgloss.Style, bool) {
+	visual := m.visualRange()
+	status := statusColumn(4)
+	return func(row table.Row, col int) (lipgloss.Style, bool) {
+		style, ok := status(row, col)
+		if m.marked[releaseKey(row)] || visual[releaseKey(row)] {
+			return lipgloss.NewStyle().Foreground(styles.CurrentTheme.Highlight).Underline(true).Transform(style.GetTransform()), true
+		}
+		if changed, ok := m.changedStyle(row, col); ok {
+			return changed.Transform(style.GetTransform()), true
+		}
+		return style, ok
+	}
+}
+
+func (m Model) renderBulk() string {
+	b := m.bulk
+	var lines []string
+	for _, job := range b.jobs {
+		line := "  … " + releaseKey(job.release)
+		switch {
+		case job.err != nil:
+			line = lipgloss.NewStyle().Foreground(styles.CurrentTheme.Error).Render("  ✗ " + releaseKey(job.release) + ": " + job.err.Error())
+		case job.done:
+			line = "  ✓ " + releaseKey(job.release) + ": " + job.result
+		case !b.started:
+			line = "  • " + releaseKey(job.release)
+		}
+		lines = append(lines, line)
+	}
+	title := fmt.Sprintf(" %s %d releases ", bulkActionLabels[b.action], len(b.jobs))
+	var status string
+	switch {
+	case !b.started:
+		status = fmt.Sprintf("Confirm? %s/%s", bulkKeys.Confirm.Help().Key, bulkKeys.Cancel.Help().Key)
+	case b.finished():
+		status = fmt.Sprintf("Done, %d failed. %s to close", b.failures(), bulkKeys.Back.Help().Key)
+	default:
+		done := 0
+		for _, job := range b.jobs {
+			if job.done {
+				done++
+			}
+		}
+		status = fmt.Sprintf("%d/%d done", done, len(b.jobs))
+	}
+	content := strings.Join(lines, "\n") + "\n\n  " + status
+	topBorder := styles.GenerateTopBorderWithTitle(title, m.width-2, styles.Border, styles.ActiveStyle.Foreground(styles.CurrentTheme.Highlight))
+	baseStyle := styles.ActiveStyle.Border(styles.Border, false, true, true).Width(m.width - 2).Height(m.height - 3)
+	return lipgloss.JoinVertical(lipgloss.Top, topBorder, baseStyle.Render(content))
+}
@@ -0,0 +1,163 @@
+package releases
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/pidanou/helm-tui/components"
+	"github.com/pidanou/helm-tui/styles"
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+// diffContext is the number of unchanged lines shown around the changes.
+const diffContext = 3
+
+// diffMsg holds the rendered diff between two revisions of a release.
+type diffMsg struct {
+	release  string
+	from, to int
+	content  string
+	// hunks are the lines of content the hunks start at.
+	hunks []int
+	err   error
+}
+
+// diffSection is a part of a revision compared on its own: the values or a
+// resource of the manifest.
+type diffSection struct {
+	title    string
+	old, new string
+}
+
+// diff compares the values and manifests of two revisions of the selected
+// release.
+func (m Model) diff(from, to int) tea.Cmd {
+	row := m.releaseTable.SelectedRow()
+	return func() tea.Msg {
+		if row == nil {
+			return diffMsg{err: fmt.Errorf("no release selected")}
+		}
+		if from < 1 {
+			return diffMsg{err: fmt.Errorf("revision %d has no previous revision", to)}
+		}
+		name, namespace := row[0], row[1]
+		var contents [4]string
+		var err error
+		for i, get := range []func() (string, error){
+			func() (string, error) { return m.client.GetRevisionValues(name, namespace, from) },
+			func() (string, error) { return m.client.GetRevisionValues(name, namespace, to) },
+			func() (string, error) { return m.client.GetRevisionManifest(name, namespace, from) },
+			func() (string, error) { return m.client.GetRevisionManifest(name, namespace, to) },
+		} {
+			if contents[i], err = get(); err != nil {
+				return diffMsg{release: name, from: from, to: to, err: err}
+			}
+		}
+		sections := append([]diffSection{{title: "Values", old: contents[0], new: contents[1]}}, resourceSections(contents[2], contents[3])...)
+		content, hunks := renderDiff(sections)
+		return diffMsg{release: name, from: from, to: to, content: content, hunks: hunks}
+	}
+}
+
+// resourceSections pairs the resources of two manifests, in the order of the
+// new manifest followed by the removed resources.
+func resourceSections(oldManifest, newManifest string) []diffSection {
+	old := map[string]string{}
+	for _, r := range components.SplitManifest(oldManifest) {
+		old[r.Key] = r.Doc
+	}
+	var sections []diffSection
+	seen := map[string]bool{}
+	for _, r := range components.SplitManifest(newManifest) {
+		sections = append(sections, diffSection{title: r.Key, old: old[r.Key], new: r.Doc})
+		seen[r.Key] = true
+	}
+	for _, r := range components.SplitManifest(oldManifest) {
+		if !seen[r.Key] {
+			sections = append(sections, diffSection{title: r.Key, old: r.Doc})
+		}
+	}
+	return sections
+}
+
+// renderDiff renders the changed sections as colored unified diffs and
+// returns the lines their hunks start at.
+func renderDiff(sections []diffSection) (string, []int) {
+	added := lipgloss.NewStyle().Foreground(styles.CurrentTheme.Success)
+	removed := lipgloss.NewStyle().Foreground(styles.CurrentTheme.Error)
+	hunk := lipgloss.NewStyle().Foreground(styles.CurrentTheme.Subtle)
+	header := lipgloss.NewStyle().Bold(true).Foreground(styles.CurrentTheme.Highlight)
+
+	lines := []string{""} // the summary, once counted
+	var hunks []int
+	counts := map[string]int{}
+	for _, section := range sections {
+		if section.old == section.new {
+			continue
+		}
+		state, style := "changed", header
+		switch {
+		case section.old == "":
+			state = "added"
+		case section.new == "":
+			state, style = "removed", header.Foreground(styles.CurrentTheme.Error)
+		}
+		counts[state]++
+		lines = append(lines, "", style.Render(fmt.Sprintf("━━ %s (%s)", section.title, state)))
+		a, b := splitLines(section.old), splitLines(section.new)
+		matcher := difflib.NewMatcher(a, b)
+		for _, group := range matcher.GetGroupedOpCodes(diffContext) {
+			first, last := group[0], group[len(group)-1]
+			hunks = append(hunks, len(lines))
+			lines = append(lines, hunk.Render(fmt.Sprintf("@@ -%d,%d +%d,%d @@", first.I1+1, last.I2-first.I1, first.J1+1, last.J2-first.J1)))
+			for _, op := range group {
+				if op.Tag == 'e' {
+					for _, line := range a[op.I1:op.I2] {
+						lines = append(lines, " "+line)
+					}
+					continue
+				}
+				if op.Tag == 'r' || op.Tag == 'd' {
+					for _, line := range a[op.I1:op.I2] {
+						lines = append(lines, removed.Render("-"+line))
+					}
+				}
+				if op.Tag == 'r' || op.Tag == 'i' {
+					for _, line := range b[op.J1:op.J2] {
+						lines = append(lines, added.Render("+"+line))
+					}
+				}
+			}
+		}
+	}
+	if len(counts) == 0 {
+		return "No differences", nil
+	}
+	lines[0] = fmt.Sprintf("%d changed, %d added, %d removed", counts["changed"], counts["added"], counts["removed"])
+	return strings.Join(lines, "\n"), hunks
+}
+
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
+
+// showDiff opens the diff view on the content of msg.
+func (m *Model) showDiff(msg diffMsg) {
+	m.diffing = true
+	m.diffMsg = msg
+	m.diffVP = viewport.New(m.width-2, m.height-3) // borders and help
+	m.diffVP.SetContent(detailContent(msg.content, msg.err))
+}
+
+func (m Model) renderDiff() string {
+	title := fmt.Sprintf(" Diff of %s, revision %d → %d ", m.diffMsg.release, m.diffMsg.from, m.diffMsg.to)
+	topBorder := styles.GenerateTopBorderWithTitle(title, m.diffVP.Width, styles.Border, styles.ActiveStyle.Foreground(styles.CurrentTheme.Highlight))
+	baseStyle := styles.ActiveStyle.Border(styles.Border, false, true, true)
+	return lipgloss.JoinVertical(lipgloss.Top, topBorder, baseStyle.Render(m.diffVP.View()))
+}
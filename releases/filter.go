@@ -0,0 +1,72 @@
+package releases
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/table"
+)
+
+// filterQualifiers maps the qualifiers of a filter, such as status:failed,
+// to the column of releaseCols they match.
+var filterQualifiers = map[string]int{
+	"name":      0,
+	"ns":        1,
+	"namespace": 1,
+	"status":    4,
+	"chart":     5,
+}
+
+// filterColumns are matched by the terms of a filter without qualifier:
+// name, namespace, status and chart.
+var filterColumns = []int{0, 1, 4, 5}
+
+// filterReleases keeps the rows matching every term of query. A qualified
+// term, such as ns:payments, must be contained in its column; other terms
+// fuzzy-match any of filterColumns.
+func filterReleases(rows []table.Row, query string) []table.Row {
+	terms := strings.Fields(strings.ToLower(query))
+	if len(terms) == 0 {
+		return rows
+	}
+	filtered := []table.Row{}
+	for _, row := range rows {
+		if matchesAll(row, terms) {
+			filtered = append(filtered, row)
+		}
+	}
+	return filtered
+}
+
+func matchesAll(row table.Row, terms []string) bool {
+	for _, term := range terms {
+		if !matches(row, term) {
+			return false
+		}
+	}
+	return true
+}
+
+func matches(row table.Row, term string) bool {
+	qualifier, value, found := strings.Cut(term, ":")
+	if column, ok := filterQualifiers[qualifier]; found && ok {
+		return column < len(row) && strings.Contains(strings.ToLower(row[column]), value)
+	}
+	for _, column := range filterColumns {
+		if column < len(row) && fuzzyMatch(strings.ToLower(row[column]), term) {
+			return true
+		}
+	}
+	return false
+}
+
+// fuzzyMatch reports whether the runes of pattern appear in s in order.
+func fuzzyMatch(s, pattern string) bool {
+	for _, r := range pattern {
+		i := strings.IndexRune(s, r)
+		if i < 0 {
+			return false
+		}
+		s = s[i+len(string(r)):]
+	}
+	return true
+}
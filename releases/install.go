@@ -1,12 +1,16 @@
 package releases
 
 import (
-	"fmt"
+	"errors"
 	"time"
 
 	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/pidanou/helm-tui/components"
+	"github.com/pidanou/helm-tui/helm"
 	"github.com/pidanou/helm-tui/helpers"
 	"github.com/pidanou/helm-tui/types"
 )
@@ -16,7 +20,9 @@ const (
 	installChartNameStep
 	installChartVersionStep
 	installChartNamespaceStep
+	installChartValuesFilesStep
 	installChartValuesStep
+	installChartOverridesStep
 	installChartConfirmStep
 )
 
@@ -25,35 +31,63 @@ var installInputsHelper = []string{
 	"Enter chart",
 	"Enter chart version (empty for latest)",
 	"Enter namespace (empty for default)",
+	"Add values file (empty to continue)",
 	"Edit default values ? y/n",
-	"Enter to install",
+	"Add %s key=value (empty to continue)",
+	"Enter to install, p to preview",
 }
 
 const debounce = 500 * time.Millisecond
 
 type InstallModel struct {
+	client      helm.HelmClient
 	installStep int
 	Chart       string
 	Version     string
-	Inputs      []textinput.Model
-	width       int
-	height      int
-	help        help.Model
-	keys        keyMap
-	tag         int
+	// Namespace is installed into when the namespace input is left empty,
+	// the configured default namespace if unset.
+	Namespace string
+	Inputs    []textinput.Model
+	width     int
+	height    int
+	help      help.Model
+	keys      keyMap
+	tag       int
+	// previewing shows the manifests rendered by a dry run of the install
+	// until it is confirmed or the values are edited again.
+	previewing bool
+	preview    installPreviewMsg
+	previewVP  viewport.Model
+	// invalid is why the values just edited were refused, until they are
+	// edited again or the wizard is aborted.
+	invalid *helpers.YAMLError
+	// violations are the edited values breaking the schema of the chart,
+	// shown until they are edited again or esc goes back to the values step.
+	violations   []helm.Violation
+	violationsVP viewport.Model
+	// valuesFiles are local values files passed to helm, in order, before
+	// the edited values.
+	valuesFiles components.ValuesFiles
+	// overrides are the key=value overrides of the values, passed with
+	// --set, --set-string or --set-json.
+	overrides components.Overrides
 }
 
-func InitInstallModel() InstallModel {
+func InitInstallModel(client helm.HelmClient) InstallModel {
 	chart := textinput.New()
 	version := textinput.New()
 	name := textinput.New()
 	namespace := textinput.New()
+	valuesFiles := textinput.New()
 	value := textinput.New()
+	overrides := textinput.New()
 	confirm := textinput.New()
-	inputs := []textinput.Model{name, chart, version, namespace, value, confirm}
-	m := InstallModel{installStep: installChartReleaseNameStep, Inputs: inputs, help: help.New(), keys: installKeys}
+	inputs := []textinput.Model{name, chart, version, namespace, valuesFiles, value, overrides, confirm}
+	m := InstallModel{client: client, installStep: installChartReleaseNameStep, Inputs: inputs, help: help.New(), keys: installKeys, valuesFiles: components.NewValuesFiles(), overrides: components.NewOverrides()}
 	m.Inputs[installChartNameStep].ShowSuggestions = true
 	m.Inputs[installChartVersionStep].ShowSuggestions = true
+	m.Inputs[installChartValuesFilesStep].ShowSuggestions = true
+	m.Inputs[installChartOverridesStep].ShowSuggestions = true
 	return m
 }
 
@@ -70,25 +104,40 @@ func (m InstallModel) Update(msg tea.Msg) (InstallModel, tea.Cmd) {
 		m.height = msg.Height
 		m.help.Width = msg.Width
 		m.Inputs[installChartReleaseNameStep].Width = msg.Width - 6 - len(installInputsHelper[0])
+	case installPreviewMsg:
+		m.previewing = true
+		m.preview = msg
+		m.previewVP = viewport.New(m.width-2, m.height-3) // borders and help
+		m.previewVP.SetContent(detailContent(msg.content, msg.err))
+		return m, nil
 	case types.EditorFinishedMsg:
-		m.installStep++
-		for i := 0; i <= len(m.Inputs)-1; i++ {
-			if i == int(m.installStep) {
-				cmds[i] = m.Inputs[i].Focus()
-				continue
-			}
-			m.Inputs[i].Blur()
+		if errors.As(msg.Invalid, &m.invalid) {
+			return m, nil
 		}
-		return m, tea.Batch(cmds...)
+		m.invalid = nil
+		if msg.Err == nil {
+			return m, components.CheckValues(m.client, m.Inputs[installChartNameStep].Value(), m.Inputs[installChartVersionStep].Value(), m.installOptions("y").Sources())
+		}
+		return m, m.nextStep()
+	case components.SchemaMsg:
+		if msg.Err != nil {
+			helpers.Println("checking values against the chart schema:", msg.Err)
+		}
+		if len(msg.Violations) > 0 {
+			m.violations = msg.Violations
+			m.violationsVP = components.ViolationsViewport(m.width, m.height, msg.Violations)
+			return m, nil
+		}
+		return m, m.nextStep()
+	case components.ValuePathsMsg:
+		m.Inputs[installChartOverridesStep].SetSuggestions(msg.Paths)
+		return m, nil
 	case types.InstallMsg:
+		m.previewing = false
 		m.installStep = 0
-		releaseName := m.Inputs[installChartReleaseNameStep].Value()
-		namespace := m.Inputs[installChartNamespaceStep].Value()
-		if namespace == "" {
-			namespace = "default"
-		}
-		folder := fmt.Sprintf("%s/%s/%s", helpers.UserDir, namespace, releaseName)
-		cmds = append(cmds, m.cleanValueFile(folder), m.blurAllInputs(), m.resetAllInputs())
+		m.valuesFiles.Reset()
+		m.overrides.Reset()
+		cmds = append(cmds, m.cleanValueFile(m.valuesFolder()), m.blurAllInputs(), m.resetAllInputs())
 
 		return m, tea.Batch(cmds...)
 	case types.DebounceEndMsg:
@@ -99,11 +148,71 @@ func (m InstallModel) Update(msg tea.Msg) (InstallModel, tea.Cmd) {
 			if m.Inputs[installChartVersionStep].Focused() {
 				m.Inputs[installChartVersionStep].SetSuggestions(m.searchLocalPackageVersion())
 			}
+			if m.Inputs[installChartValuesFilesStep].Focused() {
+				m.Inputs[installChartValuesFilesStep].SetSuggestions(components.PathSuggestions(m.Inputs[installChartValuesFilesStep].Value()))
+			}
 		}
 	case tea.KeyMsg:
 		m.tag++
-		switch msg.String() {
-		case "enter":
+		if m.invalid != nil {
+			switch {
+			case key.Matches(msg, m.keys.Next):
+				cmd = helpers.OpenFileAt(m.invalid.File, m.invalid.Line, 0)
+				m.invalid = nil
+				return m, cmd
+			case key.Matches(msg, m.keys.Cancel):
+				m.invalid = nil
+			default:
+				return m, nil
+			}
+		}
+		if m.violations != nil {
+			switch {
+			case key.Matches(msg, m.keys.Next):
+				line := components.FirstViolationLine(m.violations)
+				m.violations = nil
+				return m, helpers.OpenFileAt(m.installOptions("y").ValuesFile, line, 0)
+			case key.Matches(msg, m.keys.Cancel):
+				m.violations = nil
+			default:
+				m.violationsVP, cmd = m.violationsVP.Update(msg)
+			}
+			return m, cmd
+		}
+		if m.previewing {
+			switch {
+			case key.Matches(msg, m.keys.Next):
+				if m.preview.err == nil {
+					m.previewing = false
+					m.installStep = 0
+					return m, m.installPackage(m.Inputs[installChartValuesStep].Value())
+				}
+			case key.Matches(msg, components.ManifestKeys.Next):
+				components.JumpTo(&m.previewVP, m.preview.sections, true)
+			case key.Matches(msg, components.ManifestKeys.Previous):
+				components.JumpTo(&m.previewVP, m.preview.sections, false)
+			case key.Matches(msg, m.keys.Cancel):
+				// back to the values, kept for the editor to open again
+				m.previewing = false
+				m.installStep = installChartValuesStep
+				m.Inputs[installChartConfirmStep].Blur()
+				return m, m.Inputs[installChartValuesStep].Focus()
+			default:
+				m.previewVP, cmd = m.previewVP.Update(msg)
+				return m, cmd
+			}
+			return m, nil
+		}
+		if m.installStep == installChartValuesFilesStep && m.valuesFiles.Update(&m.Inputs[installChartValuesFilesStep], msg) {
+			return m, nil
+		}
+		if m.installStep == installChartOverridesStep && m.overrides.Update(&m.Inputs[installChartOverridesStep], msg) {
+			return m, nil
+		}
+		switch {
+		case key.Matches(msg, m.keys.Preview) && m.installStep == installChartConfirmStep:
+			return m, m.previewInstall
+		case key.Matches(msg, m.keys.Next):
 			if m.installStep == installChartConfirmStep {
 				m.installStep = 0
 
@@ -123,23 +232,30 @@ func (m InstallModel) Update(msg tea.Msg) (InstallModel, tea.Cmd) {
 				}
 			}
 
-			m.installStep++
-
-			for i := 0; i <= len(m.Inputs)-1; i++ {
-				if i == int(m.installStep) {
-					cmds[i] = m.Inputs[i].Focus()
-					continue
+			if m.installStep == installChartValuesFilesStep && m.Inputs[installChartValuesFilesStep].Value() != "" {
+				if m.valuesFiles.Add(m.Inputs[installChartValuesFilesStep].Value()) == nil {
+					m.Inputs[installChartValuesFilesStep].SetValue("")
 				}
-				m.Inputs[i].Blur()
+				return m, nil
+			}
+			if m.installStep == installChartOverridesStep && m.Inputs[installChartOverridesStep].Value() != "" {
+				if m.overrides.Add(m.Inputs[installChartOverridesStep].Value()) {
+					m.Inputs[installChartOverridesStep].SetValue("")
+				}
+				return m, nil
 			}
 
-			return m, tea.Batch(cmds...)
-		case "esc":
+			return m, m.nextStep()
+		case key.Matches(msg, m.keys.Cancel):
+			folder := m.valuesFolder()
 			m.installStep = 0
+			m.valuesFiles.Reset()
+			m.overrides.Reset()
 			for i := 0; i <= len(m.Inputs)-1; i++ {
 				m.Inputs[i].Blur()
 				m.Inputs[i].SetValue("")
 			}
+			return m, m.cleanValueFile(folder)
 		default:
 			return m, tea.Batch(m.updateInputs(msg), tea.Tick(debounce, func(_ time.Time) tea.Msg {
 				return types.DebounceEndMsg{Tag: m.tag}
@@ -173,3 +289,26 @@ func (m InstallModel) resetAllInputs() tea.Cmd {
 	}
 	return nil
 }
+
+// nextStep focuses the input of the step after the current one.
+func (m *InstallModel) nextStep() tea.Cmd {
+	m.installStep++
+	cmds := make([]tea.Cmd, len(m.Inputs))
+	for i := range m.Inputs {
+		if i == int(m.installStep) {
+			cmds[i] = m.Inputs[i].Focus()
+			continue
+		}
+		m.Inputs[i].Blur()
+	}
+	if m.installStep == installChartOverridesStep {
+		cmds = append(cmds, components.FetchValuePaths(m.client, m.Inputs[installChartNameStep].Value(), m.Inputs[installChartVersionStep].Value()))
+	}
+	return tea.Batch(cmds...)
+}
+
+// backOnCancel reports whether esc goes back to a step of the wizard
+// instead of closing it.
+func (m InstallModel) backOnCancel() bool {
+	return m.previewing || m.violations != nil
+}
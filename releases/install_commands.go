@@ -1,42 +1,72 @@
 package releases
 
 import (
-	"bytes"
-	"encoding/json"
 	"fmt"
 	"os"
-	"os/exec"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/pidanou/helm-tui/components"
+	"github.com/pidanou/helm-tui/config"
+	"github.com/pidanou/helm-tui/helm"
 	"github.com/pidanou/helm-tui/helpers"
 	"github.com/pidanou/helm-tui/types"
 )
 
-func (m InstallModel) installPackage(mode string) tea.Cmd {
-	chartName := m.Inputs[installChartNameStep].Value()
-	version := m.Inputs[installChartVersionStep].Value()
-	releaseName := m.Inputs[installChartReleaseNameStep].Value()
+func (m InstallModel) namespace() string {
 	namespace := m.Inputs[installChartNamespaceStep].Value()
 	if namespace == "" {
-		namespace = "default"
+		namespace = m.Namespace
 	}
-	folder := fmt.Sprintf("%s/%s/%s", helpers.UserDir, namespace, releaseName)
-	file := fmt.Sprintf("%s/values.yaml", folder)
-	return func() tea.Msg {
-		var stdout, stderr bytes.Buffer
-
-		var cmd *exec.Cmd
-		// Create the command
-		if mode == "y" {
-			cmd = exec.Command("helm", "install", releaseName, chartName, "--version", version, "--values", file, "--namespace", namespace, "--create-namespace")
-		} else {
-			cmd = exec.Command("helm", "install", releaseName, chartName, "--version", version, "--namespace", namespace, "--create-namespace")
-		}
-		cmd.Stdout = &stdout
-		cmd.Stderr = &stderr
+	if namespace == "" {
+		namespace = config.Current.Namespace()
+	}
+	return namespace
+}
+
+// valuesFolder holds the values edited for the release being installed.
+func (m InstallModel) valuesFolder() string {
+	return fmt.Sprintf("%s/%s/%s", helpers.UserDir, m.namespace(), m.Inputs[installChartReleaseNameStep].Value())
+}
+
+func (m InstallModel) installOptions(mode string) helm.InstallOptions {
+	opts := helm.InstallOptions{
+		ReleaseName: m.Inputs[installChartReleaseNameStep].Value(),
+		Chart:       m.Inputs[installChartNameStep].Value(),
+		Version:     m.Inputs[installChartVersionStep].Value(),
+		Namespace:   m.namespace(),
+		ValuesFiles: m.valuesFiles.Paths,
+	}
+	opts.Set, opts.SetString, opts.SetJSON = m.overrides.Flags()
+	if mode == "y" {
+		opts.ValuesFile = fmt.Sprintf("%s/values.yaml", m.valuesFolder())
+	}
+	return opts
+}
+
+// installPreviewMsg holds the manifests and notes rendered by a dry run of
+// an install.
+type installPreviewMsg struct {
+	content string
+	// sections are the lines of content the resource kinds and the notes
+	// start at.
+	sections []int
+	err      error
+}
 
-		// Run the command
-		err := cmd.Run()
+// previewInstall renders the install with a dry run.
+func (m InstallModel) previewInstall() tea.Msg {
+	preview, err := m.client.PreviewInstall(m.installOptions(m.Inputs[installChartValuesStep].Value()))
+	if err != nil {
+		return installPreviewMsg{err: err}
+	}
+	content, sections := components.RenderManifest(preview.Manifest, preview.Notes)
+	return installPreviewMsg{content: content, sections: sections}
+}
+
+func (m InstallModel) installPackage(mode string) tea.Cmd {
+	opts := m.installOptions(mode)
+	return func() tea.Msg {
+		err := m.client.Install(opts)
 		if err != nil {
 			return types.InstallMsg{Err: err}
 		}
@@ -45,42 +75,31 @@ func (m InstallModel) installPackage(mode string) tea.Cmd {
 	}
 }
 
+// openEditorDefaultValues edits the default values of the chart, commented
+// out under values files, or the values already edited when coming back from
+// the preview.
 func (m InstallModel) openEditorDefaultValues() tea.Cmd {
-	var stdout, stderr bytes.Buffer
-	releaseName := m.Inputs[installChartReleaseNameStep].Value()
-	namespace := m.Inputs[installChartNamespaceStep].Value()
-	if namespace == "" {
-		namespace = "default"
-	}
-	folder := fmt.Sprintf("%s/%s/%s", helpers.UserDir, namespace, releaseName)
+	folder := m.valuesFolder()
 	_ = os.MkdirAll(folder, 0755)
 	file := fmt.Sprintf("%s/values.yaml", folder)
+	if _, err := os.Stat(file); err == nil {
+		return helpers.OpenFile(file)
+	}
 	packageName := m.Inputs[installChartNameStep].Value()
 	version := m.Inputs[installChartVersionStep].Value()
 
-	cmd := exec.Command("helm", "show", "values", packageName, "--version", version)
-	cmd.Stdout = &stdout
-	cmd.Stderr = &stderr
-	err := cmd.Run()
+	values, err := m.client.ShowValues(packageName, version)
 	if err != nil {
 		return func() tea.Msg { return types.EditorFinishedMsg{Err: err} }
 	}
-	return helpers.WriteAndOpenFile(stdout.Bytes(), file)
+	return helpers.WriteAndOpenFile([]byte(m.valuesFiles.Seed(values)), file)
 }
 
 func (m InstallModel) searchLocalPackage() []string {
 	if m.Inputs[installChartNameStep].Value() == "" {
 		return []string{}
 	}
-	var stdout bytes.Buffer
-	cmd := exec.Command("helm", "search", "repo", m.Inputs[installChartNameStep].Value(), "--output", "json")
-	cmd.Stdout = &stdout
-	err := cmd.Run()
-	if err != nil {
-		return []string{}
-	}
-	var pkgs []types.Pkg
-	err = json.Unmarshal(stdout.Bytes(), &pkgs)
+	pkgs, err := m.client.SearchRepo(helm.SearchOptions{Keyword: m.Inputs[installChartNameStep].Value()})
 	if err != nil {
 		return []string{}
 	}
@@ -93,15 +112,7 @@ func (m InstallModel) searchLocalPackage() []string {
 }
 
 func (m InstallModel) searchLocalPackageVersion() []string {
-	var stdout bytes.Buffer
-	cmd := exec.Command("helm", "search", "repo", "--regexp", "\v"+m.Inputs[installChartNameStep].Value()+"\v", "--versions", "--output", "json")
-	cmd.Stdout = &stdout
-	err := cmd.Run()
-	if err != nil {
-		return []string{}
-	}
-	var pkgs []types.Pkg
-	err = json.Unmarshal(stdout.Bytes(), &pkgs)
+	pkgs, err := m.client.SearchRepo(helm.SearchOptions{Keyword: "\v" + m.Inputs[installChartNameStep].Value() + "\v", Regexp: true, Versions: true})
 	if err != nil {
 		return []string{}
 	}
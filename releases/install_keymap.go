@@ -1,7 +1,16 @@
 package releases
 
-import "github.com/charmbracelet/bubbles/key"
+import (
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/pidanou/helm-tui/helpers"
+)
 
 var installKeys = keyMap{
-	Cancel: key.NewBinding(key.WithKeys("esc"), key.WithHelp("esc", "Cancel")),
+	Next:    key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "Next")),
+	Preview: key.NewBinding(key.WithKeys("p"), key.WithHelp("p", "Preview")),
+	Cancel:  key.NewBinding(key.WithKeys("esc"), key.WithHelp("esc", "Cancel")),
+}
+
+func init() {
+	helpers.RegisterKeys("releases.install", map[string]*key.Binding{"next": &installKeys.Next, "preview": &installKeys.Preview, "cancel": &installKeys.Cancel}, "global")
 }
@@ -1,24 +1,28 @@
 package releases
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/pidanou/helm-tui/helm"
+	"github.com/pidanou/helm-tui/helpers"
 	"github.com/pidanou/helm-tui/types"
 	"github.com/stretchr/testify/assert"
 )
 
 // TestInitInstallModel verifies that the InstallModel initializes correctly.
 func TestInitInstallModel(t *testing.T) {
-	model := InitInstallModel()
+	model := InitInstallModel(helm.NewFakeClient())
 
 	assert.Equal(t, installChartReleaseNameStep, model.installStep, "Initial installStep should be installChartReleaseNameStep")
-	assert.Equal(t, 6, len(model.Inputs), "InstallModel should have 6 inputs")
+	assert.Equal(t, 8, len(model.Inputs), "InstallModel should have 8 inputs")
 }
 
 // TestInstallModelEnterKey verifies that the Enter key advances the install step.
 func TestInstallModelEnterKey(t *testing.T) {
-	model := InitInstallModel()
+	model := InitInstallModel(helm.NewFakeClient())
 	msg := tea.KeyMsg{Type: tea.KeyEnter}
 
 	updatedModel, _ := model.Update(msg)
@@ -29,7 +33,7 @@ func TestInstallModelEnterKey(t *testing.T) {
 
 // TestInstallModelEscKey verifies that the Esc key resets the install step and clears inputs.
 func TestInstallModelEscKey(t *testing.T) {
-	model := InitInstallModel()
+	model := InitInstallModel(helm.NewFakeClient())
 	model.Inputs[installChartReleaseNameStep].SetValue("test-release")
 
 	msg := tea.KeyMsg{Type: tea.KeyEsc}
@@ -43,7 +47,7 @@ func TestInstallModelEscKey(t *testing.T) {
 
 // TestInstallMsgHandling verifies that the model resets after handling an InstallMsg.
 func TestInstallMsgHandling(t *testing.T) {
-	model := InitInstallModel()
+	model := InitInstallModel(helm.NewFakeClient())
 	model.Inputs[installChartReleaseNameStep].SetValue("test-release")
 
 	msg := types.InstallMsg{}
@@ -54,3 +58,192 @@ func TestInstallMsgHandling(t *testing.T) {
 		assert.Empty(t, input.Value(), "All inputs should be cleared after InstallMsg")
 	}
 }
+
+// TestInstallPreview verifies that p at the confirm step renders the install
+// with a dry run, and that enter then installs the release.
+func TestInstallPreview(t *testing.T) {
+	helpers.UserDir = t.TempDir()
+	client := helm.NewFakeClient()
+	client.Previews["default/web"] = helm.Preview{Manifest: "kind: Service\nmetadata:\n  name: web\n", Notes: "Visit http://web"}
+	m := InitInstallModel(client)
+	m, _ = m.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	m.Inputs[installChartReleaseNameStep].SetValue("web")
+	m.Inputs[installChartNameStep].SetValue("bitnami/nginx")
+	m.Inputs[installChartNamespaceStep].SetValue("default")
+	m.Inputs[installChartValuesStep].SetValue("n")
+	m.installStep = installChartConfirmStep
+
+	m, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("p")})
+	m, _ = m.Update(cmd())
+	assert.True(t, m.previewing)
+	assert.Contains(t, m.preview.content, "━━ Service (1)")
+	assert.Contains(t, m.preview.content, "Visit http://web")
+	assert.NotContains(t, client.Calls, "Install web bitnami/nginx  default")
+
+	m, cmd = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	assert.False(t, m.previewing)
+	assert.Equal(t, types.InstallMsg{}, cmd())
+	assert.Contains(t, client.Calls, "Install web bitnami/nginx  default")
+}
+
+// TestInstallPreviewBack verifies that esc leaves the preview for the values
+// step, keeping the edited values.
+func TestInstallPreviewBack(t *testing.T) {
+	helpers.UserDir = t.TempDir()
+	client := helm.NewFakeClient()
+	client.Previews["default/web"] = helm.Preview{}
+	m := InitInstallModel(client)
+	m.Inputs[installChartReleaseNameStep].SetValue("web")
+	m.Inputs[installChartNamespaceStep].SetValue("default")
+	m.Inputs[installChartValuesStep].SetValue("y")
+	m.installStep = installChartConfirmStep
+	file := filepath.Join(m.valuesFolder(), "values.yaml")
+	assert.NoError(t, os.MkdirAll(m.valuesFolder(), 0755))
+	assert.NoError(t, os.WriteFile(file, []byte("replicas: 3\n"), 0644))
+
+	m, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("p")})
+	m, _ = m.Update(cmd())
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+
+	assert.False(t, m.previewing)
+	assert.Equal(t, installChartValuesStep, m.installStep)
+	assert.True(t, m.Inputs[installChartValuesStep].Focused())
+	assert.Equal(t, "y", m.Inputs[installChartValuesStep].Value())
+	assert.FileExists(t, file)
+	assert.Contains(t, client.Calls, "PreviewInstall web   default "+file)
+}
+
+// TestInstallInvalidValues verifies that values which are not valid YAML
+// keep the wizard on the values step until they are edited again, and that
+// esc aborts the install.
+func TestInstallInvalidValues(t *testing.T) {
+	helpers.UserDir = t.TempDir()
+	model := InitInstallModel(helm.NewFakeClient())
+	model.Inputs[installChartValuesStep].SetValue("y")
+	model.installStep = installChartValuesStep
+	invalid := &helpers.YAMLError{File: "values.yaml", Line: 3, Msg: "mapping values are not allowed in this context"}
+
+	model, _ = model.Update(types.EditorFinishedMsg{Invalid: invalid})
+	assert.Equal(t, installChartValuesStep, model.installStep)
+	assert.Equal(t, invalid, model.invalid)
+	assert.Contains(t, model.View(), "line 3: mapping values")
+
+	model, cmd := model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	assert.NotNil(t, cmd, "enter should open the editor again")
+	assert.Nil(t, model.invalid)
+
+	model, _ = model.Update(types.EditorFinishedMsg{Invalid: invalid})
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	assert.Nil(t, model.invalid)
+	assert.Equal(t, installChartReleaseNameStep, model.installStep)
+	assert.Empty(t, model.Inputs[installChartValuesStep].Value())
+}
+
+// TestInstallSchemaViolations verifies that edited values breaking the
+// schema of the chart are listed before going on to the confirm step.
+func TestInstallSchemaViolations(t *testing.T) {
+	helpers.UserDir = t.TempDir()
+	client := helm.NewFakeClient()
+	client.Schemas["bitnami/nginx"] = `{"type": "object", "properties": {"replicaCount": {"type": "integer"}}}`
+	model := InitInstallModel(client)
+	model, _ = model.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	model.Inputs[installChartReleaseNameStep].SetValue("web")
+	model.Inputs[installChartNameStep].SetValue("bitnami/nginx")
+	model.Inputs[installChartValuesStep].SetValue("y")
+	model.installStep = installChartValuesStep
+	file := model.installOptions("y").ValuesFile
+	assert.NoError(t, os.MkdirAll(filepath.Dir(file), 0755))
+	assert.NoError(t, os.WriteFile(file, []byte("replicaCount: two\n"), 0644))
+
+	model, cmd := model.Update(types.EditorFinishedMsg{})
+	model, _ = model.Update(cmd())
+	assert.Equal(t, installChartValuesStep, model.installStep)
+	assert.Len(t, model.violations, 1)
+	assert.True(t, model.backOnCancel())
+	assert.Contains(t, model.View(), "$.replicaCount")
+
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	assert.Nil(t, model.violations)
+	assert.Equal(t, installChartValuesStep, model.installStep)
+	assert.Equal(t, "y", model.Inputs[installChartValuesStep].Value())
+
+	assert.NoError(t, os.WriteFile(file, []byte("replicaCount: 2\n"), 0644))
+	model, cmd = model.Update(types.EditorFinishedMsg{})
+	model, _ = model.Update(cmd())
+	assert.Nil(t, model.violations)
+	assert.Equal(t, installChartOverridesStep, model.installStep)
+}
+
+// TestInstallOverrides verifies that the overrides step suggests the paths of
+// the default values and passes the listed overrides to the install.
+func TestInstallOverrides(t *testing.T) {
+	helpers.UserDir = t.TempDir()
+	client := helm.NewFakeClient()
+	client.ChartValues = map[string]string{"bitnami/nginx": "image:\n  tag: latest\nreplicaCount: 1\n"}
+	model := InitInstallModel(client)
+	model.Inputs[installChartReleaseNameStep].SetValue("web")
+	model.Inputs[installChartNameStep].SetValue("bitnami/nginx")
+	model.Inputs[installChartNamespaceStep].SetValue("default")
+	model.Inputs[installChartValuesStep].SetValue("n")
+	model.installStep = installChartValuesStep
+
+	model, cmd := model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	assert.Equal(t, installChartOverridesStep, model.installStep)
+	for _, msg := range runBatch(cmd) {
+		model, _ = model.Update(msg)
+	}
+	assert.Equal(t, []string{"image", "image.tag", "replicaCount"}, model.Inputs[installChartOverridesStep].AvailableSuggestions())
+
+	for _, entry := range []string{"replicaCount=2", "image.tag=1.27", "replicaCount=3"} {
+		model.Inputs[installChartOverridesStep].SetValue(entry)
+		model, _ = model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+		assert.Empty(t, model.Inputs[installChartOverridesStep].Value())
+	}
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyCtrlT})
+	model.Inputs[installChartOverridesStep].SetValue("podLabels.version=1.0")
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	assert.Contains(t, model.View(), "--set-string podLabels.version=1.0")
+
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	assert.Equal(t, installChartConfirmStep, model.installStep)
+	model, cmd = model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	assert.Equal(t, types.InstallMsg{}, cmd())
+	assert.Contains(t, client.Calls, "Install web bitnami/nginx  default  --set replicaCount=3 --set image.tag=1.27 --set-string podLabels.version=1.0")
+}
+
+// TestInstallValuesFiles verifies that the values files are passed to the
+// install in the merge order shown.
+func TestInstallValuesFiles(t *testing.T) {
+	helpers.UserDir = t.TempDir()
+	dir := t.TempDir()
+	base, prod := filepath.Join(dir, "base.yaml"), filepath.Join(dir, "prod.yaml")
+	assert.NoError(t, os.WriteFile(base, []byte("replicaCount: 1\n"), 0644))
+	assert.NoError(t, os.WriteFile(prod, []byte("replicaCount: 3\n"), 0644))
+	client := helm.NewFakeClient()
+	model := InitInstallModel(client)
+	model.Inputs[installChartReleaseNameStep].SetValue("web")
+	model.Inputs[installChartNameStep].SetValue("bitnami/nginx")
+	model.Inputs[installChartNamespaceStep].SetValue("default")
+	model.installStep = installChartValuesFilesStep
+	model.Inputs[installChartValuesFilesStep].Focus()
+
+	for _, file := range []string{prod, base, filepath.Join(dir, "missing.yaml")} {
+		model.Inputs[installChartValuesFilesStep].SetValue(file)
+		model, _ = model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	}
+	assert.Contains(t, model.View(), "no such file")
+	model.Inputs[installChartValuesFilesStep].SetValue("")
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyDown})
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyShiftDown})
+	assert.Equal(t, []string{base, prod}, model.valuesFiles.Paths)
+	assert.Contains(t, model.View(), "2. "+prod)
+
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	assert.Equal(t, installChartValuesStep, model.installStep)
+	model.Inputs[installChartValuesStep].SetValue("n")
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	model, cmd := model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	assert.Equal(t, types.InstallMsg{}, cmd())
+	assert.Contains(t, client.Calls, "Install web bitnami/nginx  default "+base+" "+prod)
+}
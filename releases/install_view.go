@@ -4,25 +4,73 @@ import (
 	"fmt"
 
 	"github.com/charmbracelet/lipgloss"
+	"github.com/pidanou/helm-tui/components"
 	"github.com/pidanou/helm-tui/helpers"
 	"github.com/pidanou/helm-tui/styles"
 )
 
 func (m InstallModel) View() string {
+	if m.previewing {
+		return m.renderPreview()
+	}
+	if m.violations != nil {
+		return components.ViolationsView(m.violationsVP, m.help, m.Inputs[installChartNameStep].Value(), m.keys.Next, m.keys.Cancel)
+	}
 	helperStyle := m.help.Styles.ShortSeparator
-	helpView := m.help.View(m.keys) + helperStyle.Render(" • ") + m.help.View(helpers.CommonKeys)
+	keys := m.keys
+	keys.Preview.SetEnabled(m.installStep == installChartConfirmStep)
+	helpView := m.help.View(keys) + helperStyle.Render(" • ") + m.help.View(helpers.CommonKeys)
 	if m.Inputs[installChartNameStep].Focused() {
-		helpView = m.help.View(m.keys) + helperStyle.Render(" • ") + m.help.View(helpers.SuggestionInputKeyMap) + helperStyle.Render(" • ") + m.help.View(helpers.CommonKeys)
+		helpView = m.help.View(keys) + helperStyle.Render(" • ") + m.help.View(helpers.SuggestionInputKeyMap) + helperStyle.Render(" • ") + m.help.View(helpers.CommonKeys)
+	}
+	if m.Inputs[installChartValuesFilesStep].Focused() {
+		helpView = m.help.View(keys) + helperStyle.Render(" • ") + m.help.View(components.ValuesFilesKeys) + helperStyle.Render(" • ") + m.help.View(helpers.SuggestionInputKeyMap)
+	}
+	if m.Inputs[installChartOverridesStep].Focused() {
+		helpView = m.help.View(keys) + helperStyle.Render(" • ") + m.help.View(components.OverridesKeys) + helperStyle.Render(" • ") + m.help.View(helpers.SuggestionInputKeyMap)
 	}
 	var inputs string
 	for step := 0; step < len(m.Inputs); step++ {
+		helper := installInputsHelper[step]
+		if step == installChartNamespaceStep && m.Namespace != "" {
+			helper = fmt.Sprintf("Enter namespace (empty for %s)", m.Namespace)
+		}
+		if step == installChartOverridesStep {
+			helper = fmt.Sprintf(helper, m.overrides.Kind.Flag())
+		}
 		if step == 0 {
-			inputs = fmt.Sprintf("%s %s", installInputsHelper[step], m.Inputs[step].View())
+			inputs = fmt.Sprintf("%s %s", helper, m.Inputs[step].View())
 			continue
 		}
-		inputs = lipgloss.JoinVertical(lipgloss.Top, inputs, fmt.Sprintf("%s %s", installInputsHelper[step], m.Inputs[step].View()))
+		inputs = lipgloss.JoinVertical(lipgloss.Top, inputs, fmt.Sprintf("%s %s", helper, m.Inputs[step].View()))
+		if step == installChartValuesFilesStep {
+			if files := m.valuesFiles.View(m.Inputs[installChartValuesStep].Value() == "y"); files != "" {
+				inputs = lipgloss.JoinVertical(lipgloss.Top, inputs, files)
+			}
+		}
+		if step == installChartOverridesStep && len(m.overrides.Items) > 0 {
+			inputs = lipgloss.JoinVertical(lipgloss.Top, inputs, m.overrides.View())
+		}
 	}
 	inputs = styles.ActiveStyle.Border(styles.Border).Render(inputs)
 	inputs = lipgloss.JoinVertical(lipgloss.Top, inputs)
+	if m.invalid != nil {
+		keys := keyMap{}
+		keys.Next, keys.Cancel = components.InvalidValuesKeys(m.invalid, m.keys.Next, m.keys.Cancel)
+		return lipgloss.JoinVertical(lipgloss.Top, inputs, components.InvalidValues(m.invalid), m.help.View(keys))
+	}
 	return lipgloss.JoinVertical(lipgloss.Top, inputs, helpView)
 }
+
+// renderPreview shows the manifests and notes of the install to confirm.
+func (m InstallModel) renderPreview() string {
+	title := fmt.Sprintf(" Preview of installing %s as %s in %s ", m.Inputs[installChartNameStep].Value(), m.Inputs[installChartReleaseNameStep].Value(), m.namespace())
+	topBorder := styles.GenerateTopBorderWithTitle(title, m.previewVP.Width, styles.Border, styles.ActiveStyle.Foreground(styles.CurrentTheme.Highlight))
+	baseStyle := styles.ActiveStyle.Border(styles.Border, false, true, true)
+	keys := keyMap{Next: m.keys.Next, Cancel: m.keys.Cancel}
+	keys.Next.SetHelp(m.keys.Next.Help().Key, "Install")
+	keys.Next.SetEnabled(m.preview.err == nil)
+	keys.Cancel.SetHelp(m.keys.Cancel.Help().Key, "Edit values")
+	helpView := m.help.View(components.ManifestKeys) + m.help.Styles.ShortSeparator.Render(" • ") + m.help.View(keys)
+	return lipgloss.JoinVertical(lipgloss.Top, topBorder, baseStyle.Render(m.previewVP.View()), helpView)
+}
@@ -0,0 +1,117 @@
+package releases
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/pidanou/helm-tui/helpers"
+	"github.com/pidanou/helm-tui/types"
+)
+
+// namespacesFile stores, per kube context, the namespaces the Releases tab
+// was last scoped to.
+const namespacesFile = "namespaces.json"
+
+func readNamespacesFile() (map[string][]string, error) {
+	scopes := map[string][]string{}
+	content, err := os.ReadFile(filepath.Join(helpers.UserDir, namespacesFile))
+	if errors.Is(err, fs.ErrNotExist) {
+		return scopes, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	err = json.Unmarshal(content, &scopes)
+	if err != nil {
+		return nil, err
+	}
+	return scopes, nil
+}
+
+// loadNamespaces returns the namespaces remembered for a kube context, nil
+// meaning all namespaces.
+func loadNamespaces(kubeContext string) []string {
+	scopes, err := readNamespacesFile()
+	if err != nil {
+		return nil
+	}
+	return scopes[kubeContext]
+}
+
+func saveNamespaces(kubeContext string, namespaces []string) error {
+	scopes, err := readNamespacesFile()
+	if err != nil {
+		scopes = map[string][]string{}
+	}
+	if len(namespaces) == 0 {
+		delete(scopes, kubeContext)
+	} else {
+		scopes[kubeContext] = namespaces
+	}
+	content, err := json.MarshalIndent(scopes, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(helpers.UserDir, namespacesFile), content, 0644)
+}
+
+// parseNamespaces splits a comma or space separated list of namespaces,
+// dropping duplicates. An empty list means all namespaces.
+func parseNamespaces(value string) []string {
+	var namespaces []string
+	for _, ns := range strings.FieldsFunc(value, func(r rune) bool { return r == ',' || r == ' ' }) {
+		if !slices.Contains(namespaces, ns) {
+			namespaces = append(namespaces, ns)
+		}
+	}
+	return namespaces
+}
+
+// namespaceSuggestions completes the namespace being typed at the end of
+// value with the known namespaces not selected yet.
+func namespaceSuggestions(value string, known []string) []string {
+	start := strings.LastIndexAny(value, ", ") + 1
+	prefix := value[:start]
+	chosen := parseNamespaces(prefix)
+	suggestions := []string{}
+	for _, ns := range known {
+		if !slices.Contains(chosen, ns) {
+			suggestions = append(suggestions, prefix+ns)
+		}
+	}
+	return suggestions
+}
+
+// mergeNamespaces adds namespaces to known, keeping it sorted and unique.
+func mergeNamespaces(known []string, namespaces ...string) []string {
+	for _, ns := range namespaces {
+		if ns != "" && !slices.Contains(known, ns) {
+			known = append(known, ns)
+		}
+	}
+	slices.Sort(known)
+	return known
+}
+
+func scopeLabel(namespaces []string) string {
+	if len(namespaces) == 0 {
+		return "all namespaces"
+	}
+	return strings.Join(namespaces, ", ")
+}
+
+func (m Model) listNamespaces() tea.Msg {
+	namespaces, err := m.client.ListNamespaces()
+	return types.NamespacesMsg{Content: namespaces, Err: err}
+}
+
+func (m Model) rememberNamespaces() tea.Msg {
+	_ = saveNamespaces(m.client.KubeContext(), m.namespaces)
+	return nil
+}
@@ -1,11 +1,19 @@
 package releases
 
 import (
+	"strconv"
+	"strings"
+
 	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/table"
+	"github.com/charmbracelet/bubbles/textinput"
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/pidanou/helm-tui/components"
+	"github.com/pidanou/helm-tui/config"
+	"github.com/pidanou/helm-tui/helm"
+	"github.com/pidanou/helm-tui/helpers"
 	"github.com/pidanou/helm-tui/types"
 )
 
@@ -22,38 +30,66 @@ const (
 )
 
 type Model struct {
-	selectedView selectedView
-	keys         []keyMap
-	help         help.Model
-	releaseTable table.Model
-	historyTable table.Model
-	notesVP      viewport.Model
-	metadataVP   viewport.Model
-	hooksVP      viewport.Model
-	valuesVP     viewport.Model
-	manifestVP   viewport.Model
-	installModel InstallModel
-	installing   bool
-	upgradeModel UpgradeModel
-	upgrading    bool
-	deleting     bool
-	width        int
-	height       int
+	client             helm.HelmClient
+	selectedView       selectedView
+	keys               []keyMap
+	help               help.Model
+	releaseTable       table.Model
+	historyTable       table.Model
+	notesVP            viewport.Model
+	metadataVP         viewport.Model
+	hooksVP            viewport.Model
+	valuesVP           viewport.Model
+	manifestVP         viewport.Model
+	installModel       InstallModel
+	installing         bool
+	upgradeModel       UpgradeModel
+	upgrading          bool
+	deleting           bool
+	namespaces         []string
+	knownNamespaces    []string
+	namespaceInput     textinput.Model
+	selectingNamespace bool
+	// releases holds the last listing, filterInput narrows it down to the
+	// rows of releaseTable.
+	releases    []table.Row
+	filterInput textinput.Model
+	// marked holds the keys of the releases bulk actions run on, visualStart
+	// the row a range being marked starts at, -1 when none is.
+	marked      map[string]bool
+	visualStart int
+	bulk        bulkRun
+	// diffBase is the revision picked to be compared, 0 when none is.
+	diffBase int
+	diffing  bool
+	diffMsg  diffMsg
+	diffVP   viewport.Model
+	// revisions holds the revision and status of every listed release, to
+	// highlight the releases changed by the next listing.
+	revisions map[string]string
+	changed   map[string]bool
+	// historyOf is the release historyTable lists, as namespace/name.
+	historyOf string
+	// startNamespace and openRelease are applied once, at startup.
+	startNamespace string
+	openRelease    string
+	width          int
+	height         int
 }
 
 var releaseCols = []components.ColumnDefinition{
 	{Title: "Name", FlexFactor: 1},
 	{Title: "Namespace", FlexFactor: 1},
-	{Title: "Revision", Width: 10},
-	{Title: "Updated", Width: 36},
+	{Title: "Revision", Width: 10, Sort: components.SortNumber},
+	{Title: "Updated", Width: 36, Sort: components.SortTime},
 	{Title: "Status", FlexFactor: 1},
 	{Title: "Chart", FlexFactor: 1},
 	{Title: "App version", FlexFactor: 1},
 }
 
 var historyCols = []components.ColumnDefinition{
-	{Title: "Revision", FlexFactor: 1},
-	{Title: "Updated", Width: 36},
+	{Title: "Revision", FlexFactor: 1, Sort: components.SortNumber},
+	{Title: "Updated", Width: 36, Sort: components.SortTime},
 	{Title: "Status", FlexFactor: 1},
 	{Title: "Chart", FlexFactor: 1},
 	{Title: "App version", FlexFactor: 1},
@@ -71,28 +107,70 @@ var menuItem = []string{
 
 var releaseTableCache table.Model
 
-func InitModel() (Model, tea.Cmd) {
+func InitModel(client helm.HelmClient) (Model, tea.Cmd) {
 	table := components.GenerateTable()
 	k := generateKeys()
-	m := Model{releaseTable: table, historyTable: table, help: help.New(), keys: k, upgrading: false,
-		installModel: InitInstallModel(), installing: false, upgradeModel: InitUpgradeModel(), deleting: false,
+	m := Model{client: client, releaseTable: table, historyTable: table, help: help.New(), keys: k, upgrading: false,
+		installModel: InitInstallModel(client), installing: false, upgradeModel: InitUpgradeModel(client), deleting: false,
+		namespaceInput: textinput.New(), filterInput: textinput.New(), visualStart: -1,
 	}
+	m.filterInput.Prompt = "/ "
+	m.filterInput.Placeholder = "Filter by name, namespace, chart or status, e.g. status:failed ns:payments"
+	m.namespaceInput.Placeholder = "Namespaces separated by commas, empty for all namespaces"
+	m.namespaceInput.ShowSuggestions = true
 
 	m.releaseTable.Focus()
 	return m, nil
 }
 
+// StartIn scopes the releases to namespace instead of the remembered
+// namespaces and opens the details of release, given as namespace/name, once
+// listed. Either may be empty.
+func (m Model) StartIn(namespace, release string) Model {
+	m.startNamespace = namespace
+	m.openRelease = release
+	return m
+}
+
+// Init only fetches the cluster namespaces: releases are listed once the
+// working directory holding the remembered namespaces is ready.
 func (m Model) Init() tea.Cmd {
-	return m.list
+	return m.listNamespaces
+}
+
+// readOnly reports whether changes are refused in the current kube context.
+func (m Model) readOnly() bool {
+	return config.Current.ReadOnlyIn(m.client.KubeContext())
+}
+
+// InputFocused reports whether the install or upgrade wizard, the namespace
+// selector, the filter, a bulk action or a diff is capturing key presses.
+func (m Model) InputFocused() bool {
+	return m.installing || m.upgrading || m.selectingNamespace || m.filterInput.Focused() || m.bulk.active() || m.diffing
+}
+
+// applyFilter narrows the listed releases down to the filter into t, keeping
+// the selection and sort order.
+func (m Model) applyFilter(t *table.Model) {
+	setRowsKeepingSelection(t, filterReleases(m.releases, m.filterInput.Value()))
+	components.Sort(t, "releases", releaseCols)
 }
 
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmd tea.Cmd
 	var cmds []tea.Cmd
+	if _, ok := msg.(refreshMsg); ok {
+		// keep ticking while a wizard is open, only the listing is skipped
+		cmds = append(cmds, refreshTick())
+		if !m.InputFocused() && !m.deleting {
+			cmds = append(cmds, m.refreshList)
+		}
+		return m, tea.Batch(cmds...)
+	}
 	if m.installing {
 		switch msg := msg.(type) {
 		case tea.KeyMsg:
-			if msg.String() == "esc" {
+			if key.Matches(msg, installKeys.Cancel) && !m.installModel.backOnCancel() {
 				m.installing = false
 			}
 		case types.InstallMsg:
@@ -106,7 +184,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	if m.upgrading {
 		switch msg := msg.(type) {
 		case tea.KeyMsg:
-			if msg.String() == "esc" {
+			if key.Matches(msg, upgradeKeys.Cancel) && !m.upgradeModel.backOnCancel() {
 				m.upgrading = false
 			}
 		case types.UpgradeMsg:
@@ -117,13 +195,79 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		cmds = append(cmds, cmd)
 		return m, tea.Batch(cmds...)
 	}
+	if m.selectingNamespace {
+		if msg, ok := msg.(tea.KeyMsg); ok {
+			switch {
+			case key.Matches(msg, namespaceKeys.Confirm):
+				m.namespaces = parseNamespaces(m.namespaceInput.Value())
+				m.revisions = nil
+				m.selectingNamespace = false
+				m.namespaceInput.Blur()
+				m.releaseTable.SetCursor(0)
+				return m, tea.Batch(m.rememberNamespaces, m.list)
+			case key.Matches(msg, namespaceKeys.Cancel):
+				m.selectingNamespace = false
+				m.namespaceInput.Blur()
+				return m, nil
+			}
+			m.namespaceInput, cmd = m.namespaceInput.Update(msg)
+			m.namespaceInput.SetSuggestions(namespaceSuggestions(m.namespaceInput.Value(), m.knownNamespaces))
+			return m, cmd
+		}
+	}
+	if m.filterInput.Focused() {
+		if msg, ok := msg.(tea.KeyMsg); ok {
+			switch {
+			case key.Matches(msg, filterKeys.Confirm):
+				m.filterInput.Blur()
+				return m, nil
+			case key.Matches(msg, filterKeys.Cancel):
+				m.filterInput.Blur()
+				m.filterInput.SetValue("")
+				m.applyFilter(&m.releaseTable)
+				return m, nil
+			}
+			m.filterInput, cmd = m.filterInput.Update(msg)
+			m.applyFilter(&m.releaseTable)
+			return m, cmd
+		}
+	}
+	if m.diffing {
+		if msg, ok := msg.(tea.KeyMsg); ok {
+			switch {
+			case key.Matches(msg, diffKeys.Next):
+				components.JumpTo(&m.diffVP, m.diffMsg.hunks, true)
+			case key.Matches(msg, diffKeys.Previous):
+				components.JumpTo(&m.diffVP, m.diffMsg.hunks, false)
+			case key.Matches(msg, diffKeys.Back):
+				m.diffing = false
+			default:
+				m.diffVP, cmd = m.diffVP.Update(msg)
+			}
+			return m, cmd
+		}
+	}
+	if m.bulk.active() {
+		if msg, ok := msg.(tea.KeyMsg); ok {
+			switch {
+			case !m.bulk.started && key.Matches(msg, bulkKeys.Confirm):
+				return m, m.startBulk()
+			case !m.bulk.started && key.Matches(msg, bulkKeys.Cancel, bulkKeys.Back):
+				m.bulk.jobs = nil
+			case m.bulk.finished() && key.Matches(msg, bulkKeys.Back):
+				m.bulk.jobs = nil
+				return m, m.list
+			}
+			return m, nil
+		}
+	}
 	if m.deleting {
 		switch msg := msg.(type) {
 		case tea.KeyMsg:
-			switch msg.String() {
-			case "y":
+			switch {
+			case key.Matches(msg, deleteKeys.Confirm):
 				return m, m.delete
-			case "n":
+			case key.Matches(msg, deleteKeys.Cancel):
 				m.deleting = false
 				return m, nil
 			}
@@ -159,24 +303,55 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.height = msg.Height
 		components.SetTable(&m.releaseTable, releaseCols, m.width)
 		components.SetTable(&m.historyTable, historyCols, m.width)
+		components.Sort(&m.releaseTable, "releases", releaseCols)
+		components.Sort(&m.historyTable, "history", historyCols)
 		m.notesVP = viewport.New(m.width-6, 0)
 		m.metadataVP = viewport.New(m.width-6, 0)
 		m.hooksVP = viewport.New(m.width-6, 0)
 		m.valuesVP = viewport.New(m.width-6, 0)
 		m.manifestVP = viewport.New(m.width-6, 0)
 		m.help.Width = msg.Width
+		m.namespaceInput.Width = m.width - 5
+		m.filterInput.Width = m.width - 6
 		m.installModel, _ = m.installModel.Update(msg)
 		m.upgradeModel, _ = m.upgradeModel.Update(msg)
-	case types.ListReleasesMsg:
-		if m.selectedView == releasesView {
-			m.releaseTable.SetRows(msg.Content)
+	case types.InitAppMsg:
+		m.namespaces = loadNamespaces(m.client.KubeContext())
+		if m.startNamespace != "" {
+			m.namespaces = []string{m.startNamespace}
+			m.startNamespace = ""
 		}
-		releaseTableCache = table.New(table.WithRows(msg.Content), table.WithColumns(m.releaseTable.Columns()))
+		cmds = append(cmds, m.list, refreshTick())
+	case types.NamespacesMsg:
+		m.knownNamespaces = mergeNamespaces(m.knownNamespaces, msg.Content...)
+	case refreshedMsg:
+		m.setReleases(types.ListReleasesMsg(msg))
+	case types.ListReleasesMsg:
+		m.setReleases(msg)
 		m.releaseTable, cmd = m.releaseTable.Update(msg)
 		cmds = append(cmds, cmd, m.history, m.getNotes, m.getMetadata, m.getHooks, m.getValues, m.getManifest)
+		if m.openRelease != "" && m.selectedView == releasesView {
+			for i, row := range m.releaseTable.Rows() {
+				if releaseKey(row) == m.openRelease {
+					m.releaseTable.SetCursor(i)
+					cmds = append(cmds, m.showDetails())
+				}
+			}
+			m.openRelease = ""
+		}
+	case bulkResultMsg:
+		if msg.tag == m.bulk.tag && msg.index < len(m.bulk.jobs) {
+			m.bulk.jobs[msg.index].done = true
+			m.bulk.jobs[msg.index].result = msg.result
+			m.bulk.jobs[msg.index].err = msg.err
+			if m.bulk.finished() {
+				cmds = append(cmds, m.list)
+			}
+		}
+	case diffMsg:
+		m.showDiff(msg)
 	case types.HistoryMsg:
-		m.historyTable.SetRows(msg.Content)
-		m.historyTable.SetCursor(0)
+		m.setHistory(msg.Content)
 		m.historyTable, cmd = m.historyTable.Update(msg)
 		cmds = append(cmds, cmd)
 	case types.UpgradeMsg:
@@ -190,78 +365,178 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case types.RollbackMsg:
 		cmds = append(cmds, m.history)
 		m.historyTable.SetCursor(0)
+		m.historyOf = ""
 	case types.NotesMsg:
-		m.notesVP.SetContent(msg.Content)
+		m.notesVP.SetContent(detailContent(msg.Content, msg.Err))
 		m.notesVP, cmd = m.notesVP.Update(msg)
 		cmds = append(cmds, cmd)
 	case types.MetadataMsg:
-		m.metadataVP.SetContent(msg.Content)
+		m.metadataVP.SetContent(detailContent(msg.Content, msg.Err))
 		m.metadataVP, cmd = m.metadataVP.Update(msg)
 		cmds = append(cmds, cmd)
 	case types.HooksMsg:
-		m.hooksVP.SetContent(msg.Content)
+		m.hooksVP.SetContent(detailContent(msg.Content, msg.Err))
 		m.hooksVP, cmd = m.hooksVP.Update(msg)
 		cmds = append(cmds, cmd)
 	case types.ValuesMsg:
-		m.valuesVP.SetContent(msg.Content)
+		m.valuesVP.SetContent(detailContent(msg.Content, msg.Err))
 		m.valuesVP, cmd = m.valuesVP.Update(msg)
 		cmds = append(cmds, cmd)
 	case types.ManifestMsg:
-		m.manifestVP.SetContent(msg.Content)
+		m.manifestVP.SetContent(detailContent(msg.Content, msg.Err))
 		m.manifestVP, cmd = m.manifestVP.Update(msg)
 		cmds = append(cmds, cmd)
 	case types.InstallMsg:
 		cmds = append(cmds, m.list)
+	case types.SwitchKubeContextMsg:
+		m.deleting = false
+		m.namespaces = loadNamespaces(msg.Context)
+		m.knownNamespaces = nil
+		m.revisions = nil
+		m.marked = nil
+		m.visualStart = -1
+		m.diffBase = 0
+		cmds = append(cmds, m.listNamespaces)
+		if m.selectedView != releasesView {
+			m.selectedView = releasesView
+			m.historyTable.Blur()
+			m.releaseTable = releaseTableCache
+		}
+		m.releaseTable.SetCursor(0)
+		cmds = append(cmds, m.list)
 
 	case tea.KeyMsg:
-		switch msg.String() {
-		case "i":
+		keys := m.keys[m.selectedView]
+		if m.readOnly() && key.Matches(msg, keys.Install, keys.Delete, keys.Rollback, keys.Upgrade) {
+			return m, helpers.RefuseReadOnly
+		}
+		switch {
+		case key.Matches(msg, components.SortKeys.Column, components.SortKeys.Order):
+			switch m.selectedView {
+			case releasesView:
+				components.SortKey(msg, "releases", releaseCols)
+				components.Sort(&m.releaseTable, "releases", releaseCols)
+			case historyView:
+				components.SortKey(msg, "history", historyCols)
+				components.Sort(&m.historyTable, "history", historyCols)
+			}
+		case key.Matches(msg, keys.Install):
 			m.installing = true
+			m.installModel.Namespace = ""
+			if len(m.namespaces) == 1 {
+				m.installModel.Namespace = m.namespaces[0]
+			}
 			cmd = m.installModel.Init()
 			cmds = append(cmds, cmd)
 			return m, tea.Batch(cmds...)
-		case "r":
+		case key.Matches(msg, keys.Refresh):
 			switch m.selectedView {
 			case releasesView:
 				cmds = append(cmds, m.list)
 			}
-		case "R":
+		case key.Matches(msg, keys.Filter):
+			switch m.selectedView {
+			case releasesView:
+				m.filterInput.CursorEnd()
+				return m, m.filterInput.Focus()
+			}
+		case key.Matches(msg, keys.Namespace):
+			switch m.selectedView {
+			case releasesView:
+				m.selectingNamespace = true
+				m.namespaceInput.SetValue(strings.Join(m.namespaces, ","))
+				m.namespaceInput.CursorEnd()
+				m.namespaceInput.SetSuggestions(namespaceSuggestions(m.namespaceInput.Value(), m.knownNamespaces))
+				cmds = append(cmds, m.namespaceInput.Focus(), m.listNamespaces)
+				return m, tea.Batch(cmds...)
+			}
+		case key.Matches(msg, keys.Rollback):
 			switch m.selectedView {
+			case releasesView:
+				return m, m.prepareBulk(bulkRollback)
 			case historyView:
 				return m, m.rollback
 			}
-		case "D":
+		case key.Matches(msg, keys.Diff):
+			if row := m.historyTable.SelectedRow(); row != nil {
+				revision, _ := strconv.Atoi(row[0])
+				if m.diffBase == 0 {
+					m.diffBase = revision
+					return m, nil
+				}
+				from, to := min(m.diffBase, revision), max(m.diffBase, revision)
+				if from == to {
+					from = to - 1
+				}
+				m.diffBase = 0
+				return m, m.diff(from, to)
+			}
+		case key.Matches(msg, keys.Export):
+			return m, m.prepareBulk(bulkExport)
+		case key.Matches(msg, keys.Mark):
+			if row := m.releaseTable.SelectedRow(); row != nil {
+				if m.marked == nil {
+					m.marked = map[string]bool{}
+				}
+				m.marked[releaseKey(row)] = !m.marked[releaseKey(row)]
+				m.releaseTable.MoveDown(1)
+			}
+		case key.Matches(msg, keys.Visual):
+			if m.visualStart < 0 {
+				m.visualStart = m.releaseTable.Cursor()
+				return m, nil
+			}
+			if m.marked == nil {
+				m.marked = map[string]bool{}
+			}
+			for k := range m.visualRange() {
+				m.marked[k] = true
+			}
+			m.visualStart = -1
+		case key.Matches(msg, keys.Delete):
+			if m.selectedView == releasesView && m.markedCount() > 0 {
+				return m, m.prepareBulk(bulkUninstall)
+			}
 			m.deleting = true
-		case "u":
+		case key.Matches(msg, keys.Upgrade):
+			if m.selectedView == releasesView && m.markedCount() > 0 {
+				return m, m.prepareBulk(bulkUpgrade)
+			}
+			row := m.releaseTable.SelectedRow()
+			if row == nil {
+				return m, nil
+			}
 			m.upgrading = true
-			m.upgradeModel.ReleaseName = m.releaseTable.SelectedRow()[0]
-			m.upgradeModel.Namespace = m.releaseTable.SelectedRow()[1]
+			m.upgradeModel.ReleaseName = row[0]
+			m.upgradeModel.Namespace = row[1]
 			cmd = m.upgradeModel.Init()
 			cmds = append(cmds, cmd)
 			return m, tea.Batch(cmds...)
-		case "esc":
+		case key.Matches(msg, keys.Back):
 			m.installing = false
 			m.upgrading = false
 			switch m.selectedView {
 			case releasesView:
+				if m.visualStart >= 0 {
+					m.visualStart = -1
+				} else {
+					m.marked = nil
+				}
 			default:
+				m.diffBase = 0
 				m.historyTable.SetCursor(0)
 				m.selectedView = releasesView
 				m.historyTable.Blur()
 				m.releaseTable = releaseTableCache
 			}
-		case "enter", " ":
+		case key.Matches(msg, keys.Select):
 			switch m.selectedView {
 			case releasesView:
-				m.selectedView = historyView
-				releaseTableCache = m.releaseTable
-				m.releaseTable.SetHeight(3)
-				m.releaseTable.SetRows([]table.Row{m.releaseTable.SelectedRow()})
-				m.releaseTable.GotoTop()
-				m.historyTable.Focus()
-				cmds = append(cmds, m.history, m.getNotes, m.getMetadata, m.getHooks, m.getValues, m.getManifest)
+				if m.releaseTable.SelectedRow() != nil {
+					cmds = append(cmds, m.showDetails())
+				}
 			}
-		case "l", "right":
+		case key.Matches(msg, keys.NextView):
 			switch m.selectedView {
 			case releasesView:
 			case manifestView:
@@ -269,7 +544,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			default:
 				m.selectedView++
 			}
-		case "h", "left":
+		case key.Matches(msg, keys.PrevView):
 			switch m.selectedView {
 			case releasesView:
 			case historyView:
@@ -281,3 +556,23 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	}
 	return m, tea.Batch(cmds...)
 }
+
+// showDetails opens the history and details of the selected release.
+func (m *Model) showDetails() tea.Cmd {
+	m.selectedView = historyView
+	releaseTableCache = m.releaseTable
+	m.releaseTable.SetHeight(3)
+	m.releaseTable.SetRows([]table.Row{m.releaseTable.SelectedRow()})
+	m.releaseTable.GotoTop()
+	m.historyTable.Focus()
+	return tea.Batch(m.history, m.getNotes, m.getMetadata, m.getHooks, m.getValues, m.getManifest)
+}
+
+// detailContent returns the content of a release detail tab, or the error
+// that prevented fetching it.
+func detailContent(content string, err error) string {
+	if err != nil {
+		return err.Error()
+	}
+	return content
+}
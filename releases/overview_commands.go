@@ -1,12 +1,8 @@
 package releases
 
 import (
-	"bytes"
-	"encoding/json"
 	"errors"
 	"fmt"
-	"os/exec"
-	"strings"
 
 	"github.com/charmbracelet/bubbles/table"
 	tea "github.com/charmbracelet/bubbletea"
@@ -14,22 +10,20 @@ import (
 )
 
 func (m Model) list() tea.Msg {
-	var stdout bytes.Buffer
 	var releases = []table.Row{}
 
-	// Create the command
-	cmd := exec.Command("helm", "ls", "--all-namespaces", "--output", "json")
-	cmd.Stdout = &stdout
-
-	// Run the command
-	err := cmd.Run()
-	if err != nil {
-		return types.ListReleasesMsg{Err: err}
+	// no namespace lists the releases of all namespaces
+	namespaces := m.namespaces
+	if len(namespaces) == 0 {
+		namespaces = []string{""}
 	}
 	var rls []types.Release
-	err = json.Unmarshal(stdout.Bytes(), &rls)
-	if err != nil {
-		return types.ListReleasesMsg{Content: releases}
+	for _, namespace := range namespaces {
+		nsReleases, err := m.client.ListReleases(namespace)
+		if err != nil {
+			return types.ListReleasesMsg{Err: err}
+		}
+		rls = append(rls, nsReleases...)
 	}
 
 	for _, rel := range rls {
@@ -40,27 +34,15 @@ func (m Model) list() tea.Msg {
 }
 
 func (m *Model) history() tea.Msg {
-	var stdout bytes.Buffer
-
 	if m.releaseTable.SelectedRow() == nil {
 		return types.HistoryMsg{Content: nil, Err: errors.New("no release selected")}
 	}
 
-	// Create the command
-	cmd := exec.Command("helm", "history", m.releaseTable.SelectedRow()[0], "--namespace", m.releaseTable.SelectedRow()[1], "--output", "json")
-	cmd.Stdout = &stdout
-
-	// Run the command
-	err := cmd.Run()
+	history, err := m.client.History(m.releaseTable.SelectedRow()[0], m.releaseTable.SelectedRow()[1])
 	if err != nil {
 		return types.HistoryMsg{Err: err}
 	}
-	var history []types.History
 	var rows = []table.Row{}
-	err = json.Unmarshal(stdout.Bytes(), &history)
-	if err != nil {
-		return types.HistoryMsg{Content: rows}
-	}
 
 	for _, line := range history {
 		row := []string{fmt.Sprint(line.Revision), line.Updated, line.Status, line.Chart, line.AppVersion, line.Description}
@@ -75,11 +57,7 @@ func (m *Model) delete() tea.Msg {
 		return types.DeleteMsg{Err: errors.New("No release selected")}
 	}
 
-	// Create the command
-	cmd := exec.Command("helm", "uninstall", m.releaseTable.SelectedRow()[0], "--namespace", m.releaseTable.SelectedRow()[1])
-
-	// Run the command
-	err := cmd.Run()
+	err := m.client.Uninstall(m.releaseTable.SelectedRow()[0], m.releaseTable.SelectedRow()[1])
 	if err != nil {
 		return types.DeleteMsg{Err: err}
 	}
@@ -88,11 +66,7 @@ func (m *Model) delete() tea.Msg {
 
 func (m Model) rollback() tea.Msg {
 
-	// Create the command
-	cmd := exec.Command("helm", "rollback", m.releaseTable.SelectedRow()[0], m.historyTable.SelectedRow()[0], "--namespace", m.releaseTable.SelectedRow()[1])
-
-	// Run the command
-	err := cmd.Run()
+	err := m.client.Rollback(m.releaseTable.SelectedRow()[0], m.historyTable.SelectedRow()[0], m.releaseTable.SelectedRow()[1])
 	if err != nil {
 		return types.RollbackMsg{Err: err}
 	}
@@ -100,90 +74,65 @@ func (m Model) rollback() tea.Msg {
 }
 
 func (m Model) getNotes() tea.Msg {
-	var stdout bytes.Buffer
-
 	if m.releaseTable.SelectedRow() == nil {
 		return types.NotesMsg{Err: errors.New("no release selected")}
 	}
 
-	cmd := exec.Command("helm", "get", "notes", m.releaseTable.SelectedRow()[0], "--namespace", m.releaseTable.SelectedRow()[1])
-	cmd.Stdout = &stdout
-	err := cmd.Run()
+	notes, err := m.client.GetNotes(m.releaseTable.SelectedRow()[0], m.releaseTable.SelectedRow()[1])
 	if err != nil {
 		return types.NotesMsg{Err: err}
 	}
 
-	return types.NotesMsg{Content: stdout.String(), Err: nil}
+	return types.NotesMsg{Content: notes, Err: nil}
 }
 
 func (m Model) getMetadata() tea.Msg {
-	var stdout bytes.Buffer
-
 	if m.releaseTable.SelectedRow() == nil {
 		return types.NotesMsg{Err: errors.New("no release selected")}
 	}
 
-	cmd := exec.Command("helm", "get", "metadata", m.releaseTable.SelectedRow()[0], "--namespace", m.releaseTable.SelectedRow()[1])
-	cmd.Stdout = &stdout
-	err := cmd.Run()
+	metadata, err := m.client.GetMetadata(m.releaseTable.SelectedRow()[0], m.releaseTable.SelectedRow()[1])
 	if err != nil {
 		return types.MetadataMsg{Err: err}
 	}
 
-	return types.MetadataMsg{Content: stdout.String(), Err: nil}
+	return types.MetadataMsg{Content: metadata, Err: nil}
 }
 
 func (m Model) getHooks() tea.Msg {
-	var stdout bytes.Buffer
-
 	if m.releaseTable.SelectedRow() == nil {
 		return types.HooksMsg{Err: errors.New("no release selected")}
 	}
 
-	cmd := exec.Command("helm", "get", "hooks", m.releaseTable.SelectedRow()[0], "--namespace", m.releaseTable.SelectedRow()[1])
-	cmd.Stdout = &stdout
-	err := cmd.Run()
+	hooks, err := m.client.GetHooks(m.releaseTable.SelectedRow()[0], m.releaseTable.SelectedRow()[1])
 	if err != nil {
 		return types.HooksMsg{Err: err}
 	}
 
-	return types.HooksMsg{Content: stdout.String(), Err: nil}
+	return types.HooksMsg{Content: hooks, Err: nil}
 }
 
 func (m Model) getValues() tea.Msg {
-	var stdout bytes.Buffer
-
 	if m.releaseTable.SelectedRow() == nil {
 		return types.ValuesMsg{Err: errors.New("no release selected")}
 	}
 
-	cmd := exec.Command("helm", "get", "values", m.releaseTable.SelectedRow()[0], "--namespace", m.releaseTable.SelectedRow()[1])
-	cmd.Stdout = &stdout
-	err := cmd.Run()
+	values, err := m.client.GetValues(m.releaseTable.SelectedRow()[0], m.releaseTable.SelectedRow()[1])
 	if err != nil {
 		return types.ValuesMsg{Err: err}
 	}
-	lines := strings.Split(stdout.String(), "\n")
-	if len(lines) <= 1 {
-		return types.ValuesMsg{Err: errors.New("no values found")}
-	}
-	lines = lines[1:]
 
-	return types.ValuesMsg{Content: strings.Join(lines, "\n"), Err: nil}
+	return types.ValuesMsg{Content: values, Err: nil}
 }
 
 func (m Model) getManifest() tea.Msg {
-	var stdout bytes.Buffer
-
 	if m.releaseTable.SelectedRow() == nil {
 		return types.ManifestMsg{Err: errors.New("no release selected")}
 	}
 
-	cmd := exec.Command("helm", "get", "manifest", m.releaseTable.SelectedRow()[0], "--namespace", m.releaseTable.SelectedRow()[1])
-	cmd.Stdout = &stdout
-	err := cmd.Run()
+	manifest, err := m.client.GetManifest(m.releaseTable.SelectedRow()[0], m.releaseTable.SelectedRow()[1])
 	if err != nil {
 		return types.ManifestMsg{Err: err}
 	}
-	return types.ManifestMsg{Content: stdout.String(), Err: nil}
+	return types.ManifestMsg{Content: manifest, Err: nil}
 }
@@ -2,26 +2,38 @@ package releases
 
 import (
 	"github.com/charmbracelet/bubbles/key"
+	"github.com/pidanou/helm-tui/helpers"
 )
 
 // keyMap defines a set of keybindings. To work for help it must satisfy
 // key.Map. It could also very easily be a map[string]key.Binding.
 type keyMap struct {
 	Install   key.Binding
+	Namespace key.Binding
+	Filter    key.Binding
 	Delete    key.Binding
 	Rollback  key.Binding
+	Diff      key.Binding
+	Previous  key.Binding
 	Refresh   key.Binding
 	Select    key.Binding
-	ChangeTab key.Binding
+	Mark      key.Binding
+	Visual    key.Binding
+	Export    key.Binding
+	PrevView  key.Binding
+	NextView  key.Binding
 	Back      key.Binding
 	Upgrade   key.Binding
+	Confirm   key.Binding
+	Preview   key.Binding
+	Next      key.Binding
 	Cancel    key.Binding
 }
 
 // ShortHelp returns keybindings to be shown in the mini help view. It's part
 // of the key.Map interface.
 func (k keyMap) ShortHelp() []key.Binding {
-	return []key.Binding{k.Install, k.Delete, k.Upgrade, k.Select, k.Refresh, k.Rollback, k.ChangeTab, k.Cancel, k.Back}
+	return []key.Binding{k.Install, k.Namespace, k.Filter, k.Delete, k.Upgrade, k.Select, k.Mark, k.Visual, k.Export, k.Refresh, k.Rollback, k.Diff, k.PrevView, k.NextView, k.Confirm, k.Preview, k.Next, k.Previous, k.Cancel, k.Back}
 }
 
 // FullHelp returns keybindings for the expanded help view. It's part of the
@@ -36,21 +48,30 @@ var releasesKeys = keyMap{
 		key.WithKeys("D"),
 		key.WithHelp("D", "Delete release"),
 	),
-	Refresh: key.NewBinding(key.WithKeys("r"), key.WithHelp("r", "Refresh")),
-	Select:  key.NewBinding(key.WithKeys("enter/space"), key.WithHelp("enter/space", "Details")),
-	Upgrade: key.NewBinding(key.WithKeys("u"), key.WithHelp("u", "Upgrade release")),
+	Refresh:   key.NewBinding(key.WithKeys("r"), key.WithHelp("r", "Refresh")),
+	Namespace: key.NewBinding(key.WithKeys("n"), key.WithHelp("n", "Namespaces")),
+	Filter:    key.NewBinding(key.WithKeys("/"), key.WithHelp("/", "Filter")),
+	Select:    key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "Details")),
+	Upgrade:   key.NewBinding(key.WithKeys("u"), key.WithHelp("u", "Upgrade release")),
+	Mark:      key.NewBinding(key.WithKeys(" "), key.WithHelp("space", "Mark")),
+	Visual:    key.NewBinding(key.WithKeys("v"), key.WithHelp("v", "Mark range")),
+	Rollback:  key.NewBinding(key.WithKeys("R"), key.WithHelp("R", "Rollback to previous revision")),
+	Export:    key.NewBinding(key.WithKeys("E"), key.WithHelp("E", "Export values")),
+	Back:      key.NewBinding(key.WithKeys("esc"), key.WithHelp("esc", "Clear marks")),
 }
 
 var historyKeys = keyMap{
 	Install:  key.NewBinding(key.WithKeys("i"), key.WithHelp("i", "Install new release")),
 	Rollback: key.NewBinding(key.WithKeys("R"), key.WithHelp("R", "Rollback to revision")),
+	Diff:     key.NewBinding(key.WithKeys("d"), key.WithHelp("d", "Diff two revisions")),
 	Delete: key.NewBinding(
 		key.WithKeys("D"),
 		key.WithHelp("D", "Delete release"),
 	),
-	Upgrade:   key.NewBinding(key.WithKeys("u"), key.WithHelp("u", "Upgrade release")),
-	ChangeTab: key.NewBinding(key.WithKeys("h", "l", "right", "left"), key.WithHelp("hl/←→", "Navigate tabs")),
-	Back:      key.NewBinding(key.WithKeys("esc"), key.WithHelp("esc", "Back")),
+	Upgrade:  key.NewBinding(key.WithKeys("u"), key.WithHelp("u", "Upgrade release")),
+	PrevView: key.NewBinding(key.WithKeys("h", "left"), key.WithHelp("h/←", "Previous tab")),
+	NextView: key.NewBinding(key.WithKeys("l", "right"), key.WithHelp("l/→", "Next tab")),
+	Back:     key.NewBinding(key.WithKeys("esc"), key.WithHelp("esc", "Back")),
 }
 
 var readOnlyKeys = keyMap{
@@ -59,9 +80,68 @@ var readOnlyKeys = keyMap{
 		key.WithKeys("D"),
 		key.WithHelp("D", "Delete release"),
 	),
-	Upgrade:   key.NewBinding(key.WithKeys("u"), key.WithHelp("u", "Upgrade release")),
-	ChangeTab: key.NewBinding(key.WithKeys("h", "l", "right", "left"), key.WithHelp("hl/←→", "Navigate tabs")),
-	Back:      key.NewBinding(key.WithKeys("esc"), key.WithHelp("esc", "Back")),
+	Upgrade:  key.NewBinding(key.WithKeys("u"), key.WithHelp("u", "Upgrade release")),
+	PrevView: key.NewBinding(key.WithKeys("h", "left"), key.WithHelp("h/←", "Previous tab")),
+	NextView: key.NewBinding(key.WithKeys("l", "right"), key.WithHelp("l/→", "Next tab")),
+	Back:     key.NewBinding(key.WithKeys("esc"), key.WithHelp("esc", "Back")),
+}
+
+var deleteKeys = keyMap{
+	Confirm: key.NewBinding(key.WithKeys("y"), key.WithHelp("y", "Delete")),
+	Cancel:  key.NewBinding(key.WithKeys("n"), key.WithHelp("n", "Cancel")),
+}
+
+var filterKeys = keyMap{
+	Confirm: key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "Keep filter")),
+	Cancel:  key.NewBinding(key.WithKeys("esc"), key.WithHelp("esc", "Clear filter")),
+}
+
+var diffKeys = keyMap{
+	Next:     key.NewBinding(key.WithKeys("n"), key.WithHelp("n", "Next hunk")),
+	Previous: key.NewBinding(key.WithKeys("N"), key.WithHelp("N", "Previous hunk")),
+	Back:     key.NewBinding(key.WithKeys("esc"), key.WithHelp("esc", "Close")),
+}
+
+// bulkKeys confirm, then close, an action run on the marked releases.
+var bulkKeys = keyMap{
+	Confirm: key.NewBinding(key.WithKeys("y"), key.WithHelp("y", "Run")),
+	Cancel:  key.NewBinding(key.WithKeys("n"), key.WithHelp("n", "Cancel")),
+	Back:    key.NewBinding(key.WithKeys("esc"), key.WithHelp("esc", "Close")),
+}
+
+var namespaceKeys = keyMap{
+	Confirm: key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "Apply")),
+	Cancel:  key.NewBinding(key.WithKeys("esc"), key.WithHelp("esc", "Cancel")),
+}
+
+func init() {
+	helpers.RegisterKeys("releases", map[string]*key.Binding{
+		"install": &releasesKeys.Install, "delete": &releasesKeys.Delete, "refresh": &releasesKeys.Refresh,
+		"namespaces": &releasesKeys.Namespace, "filter": &releasesKeys.Filter, "details": &releasesKeys.Select, "upgrade": &releasesKeys.Upgrade,
+		"mark": &releasesKeys.Mark, "markRange": &releasesKeys.Visual, "rollback": &releasesKeys.Rollback,
+		"export": &releasesKeys.Export, "back": &releasesKeys.Back,
+	}, "global", "table")
+	for _, keys := range []*keyMap{&historyKeys, &readOnlyKeys} {
+		helpers.RegisterKeys("releases", map[string]*key.Binding{
+			"install": &keys.Install, "delete": &keys.Delete, "upgrade": &keys.Upgrade,
+			"previousView": &keys.PrevView, "nextView": &keys.NextView, "back": &keys.Back,
+		})
+	}
+	helpers.RegisterKeys("releases", map[string]*key.Binding{"rollback": &historyKeys.Rollback, "diff": &historyKeys.Diff})
+	helpers.RegisterKeys("releases.diff", map[string]*key.Binding{"nextHunk": &diffKeys.Next, "previousHunk": &diffKeys.Previous, "close": &diffKeys.Back}, "global")
+	helpers.RegisterKeys("releases.delete", map[string]*key.Binding{"confirm": &deleteKeys.Confirm, "cancel": &deleteKeys.Cancel}, "global")
+	helpers.RegisterKeys("releases.bulk", map[string]*key.Binding{"confirm": &bulkKeys.Confirm, "cancel": &bulkKeys.Cancel, "close": &bulkKeys.Back}, "global")
+	helpers.RegisterKeys("releases.filter", map[string]*key.Binding{"keep": &filterKeys.Confirm, "clear": &filterKeys.Cancel}, "global")
+	helpers.RegisterKeys("releases.namespaces", map[string]*key.Binding{"apply": &namespaceKeys.Confirm, "cancel": &namespaceKeys.Cancel}, "global")
+}
+
+// withoutChanges hides the bindings refused in read-only mode.
+func (k keyMap) withoutChanges() keyMap {
+	k.Install.SetEnabled(false)
+	k.Delete.SetEnabled(false)
+	k.Rollback.SetEnabled(false)
+	k.Upgrade.SetEnabled(false)
+	return k
 }
 
 func generateKeys() []keyMap {
@@ -1 +1,446 @@
 package releases
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/pidanou/helm-tui/config"
+	"github.com/pidanou/helm-tui/helm"
+	"github.com/pidanou/helm-tui/helpers"
+	"github.com/pidanou/helm-tui/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestClient() *helm.FakeClient {
+	client := helm.NewFakeClient()
+	client.Releases = []types.Release{
+		{Name: "web", Namespace: "default", Revision: "1", Status: "deployed", Chart: "nginx-1.0.0"},
+		{Name: "db", Namespace: "data", Revision: "3", Status: "failed", Chart: "postgresql-12.0.0"},
+	}
+	return client
+}
+
+func newTestModel(client helm.HelmClient) tea.Model {
+	m, _ := InitModel(client)
+	updated, _ := m.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	return updated
+}
+
+// TestListReleases verifies that the releases returned by the client fill the table.
+func TestListReleases(t *testing.T) {
+	m := newTestModel(newTestClient())
+
+	msg := m.(Model).list().(types.ListReleasesMsg)
+	updated, _ := m.Update(msg)
+
+	rows := updated.(Model).releaseTable.Rows()
+	assert.NoError(t, msg.Err)
+	assert.Len(t, rows, 2, "Every release should be listed")
+	assert.Equal(t, "db", rows[1][0])
+	assert.Equal(t, "failed", rows[1][4])
+}
+
+// TestDeleteRelease verifies that confirming a deletion uninstalls the selected release.
+func TestDeleteRelease(t *testing.T) {
+	client := newTestClient()
+	m := newTestModel(client)
+	updated, _ := m.Update(m.(Model).list())
+
+	updated, _ = updated.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("D")})
+	assert.True(t, updated.(Model).deleting, "D should ask for confirmation")
+
+	updated, cmd := updated.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("y")})
+	msg := cmd()
+
+	assert.IsType(t, types.DeleteMsg{}, msg)
+	assert.Equal(t, []string{"ListReleases", "Uninstall web default"}, client.Calls)
+	assert.Len(t, client.Releases, 1)
+
+	updated, _ = updated.Update(msg)
+	assert.False(t, updated.(Model).deleting, "Model should leave the confirmation after a DeleteMsg")
+}
+
+// TestListReleasesError verifies that client errors are reported in the message.
+func TestListReleasesError(t *testing.T) {
+	client := newTestClient()
+	client.Err = assert.AnError
+	m, _ := InitModel(client)
+
+	msg := m.list().(types.ListReleasesMsg)
+
+	assert.ErrorIs(t, msg.Err, assert.AnError)
+	assert.Empty(t, msg.Content)
+}
+
+// TestNamespaceScope verifies that the selected namespaces scope the release
+// list, are remembered and become the default install namespace.
+func TestNamespaceScope(t *testing.T) {
+	helpers.UserDir = t.TempDir()
+	client := newTestClient()
+	m := newTestModel(client)
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("n")})
+	assert.True(t, updated.(Model).InputFocused(), "n should open the namespace selector")
+	updated, _ = updated.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("data")})
+	updated, cmd := updated.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	assert.NotNil(t, cmd)
+
+	model := updated.(Model)
+	assert.Equal(t, []string{"data"}, model.namespaces)
+	msg := model.list().(types.ListReleasesMsg)
+	assert.Len(t, msg.Content, 1)
+	assert.Equal(t, "db", msg.Content[0][0])
+
+	model.rememberNamespaces()
+	assert.Equal(t, []string{"data"}, loadNamespaces(""))
+
+	updated, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("i")})
+	assert.Equal(t, "data", updated.(Model).installModel.namespace())
+}
+
+// TestStartIn verifies that the startup namespace replaces the remembered ones and that the startup release opens in the details view.
+func TestStartIn(t *testing.T) {
+	helpers.UserDir = t.TempDir()
+	m, _ := InitModel(newTestClient())
+	updated, _ := m.StartIn("data", "data/db").Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+
+	updated, _ = updated.Update(types.InitAppMsg{})
+	model := updated.(Model)
+	assert.Equal(t, []string{"data"}, model.namespaces)
+
+	updated, _ = model.Update(model.list())
+	model = updated.(Model)
+	assert.Equal(t, historyView, model.selectedView)
+	assert.Equal(t, "db", model.releaseTable.SelectedRow()[0])
+	assert.Empty(t, model.openRelease, "The release should only be opened once")
+}
+
+// TestReadOnly verifies that changes are refused and hidden from the help in read-only mode.
+func TestReadOnly(t *testing.T) {
+	config.Current.ReadOnly = true
+	t.Cleanup(func() { config.Current = config.Default() })
+	client := newTestClient()
+	m := newTestModel(client)
+	updated, _ := m.Update(m.(Model).list())
+
+	for _, k := range []string{"i", "D", "u"} {
+		updated, cmd := updated.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k)})
+
+		assert.False(t, updated.(Model).InputFocused() || updated.(Model).deleting, k)
+		assert.Equal(t, types.ReadOnlyMsg{Err: helpers.ErrReadOnly}, cmd(), k)
+	}
+	assert.Equal(t, []string{"ListReleases"}, client.Calls)
+	assert.NotContains(t, updated.View(), "Delete release")
+	assert.Contains(t, updated.View(), "Details")
+}
+
+// TestNamespaceSuggestions verifies that only the namespace being typed is completed.
+func TestNamespaceSuggestions(t *testing.T) {
+	known := []string{"data", "default", "payments"}
+
+	assert.Equal(t, []string{"data", "default", "payments"}, namespaceSuggestions("d", known))
+	assert.Equal(t, []string{"data, default", "data, payments"}, namespaceSuggestions("data, d", known))
+	assert.Equal(t, []string{"data", "payments"}, parseNamespaces("data, payments,data"))
+}
+
+// TestRefreshKeepsSelection verifies that listing again keeps the cursor on the same release and flags the changed releases.
+func TestRefreshKeepsSelection(t *testing.T) {
+	client := newTestClient()
+	m := newTestModel(client)
+	updated, _ := m.Update(m.(Model).list())
+	updated, _ = updated.Update(tea.KeyMsg{Type: tea.KeyDown})
+	assert.Equal(t, "db", updated.(Model).releaseTable.SelectedRow()[0])
+
+	client.Releases = append([]types.Release{{Name: "cache", Namespace: "data", Revision: "1", Status: "deployed"}}, client.Releases...)
+	client.Releases[1].Revision = "2"
+	updated, _ = updated.Update(updated.(Model).list())
+
+	model := updated.(Model)
+	assert.Equal(t, "db", model.releaseTable.SelectedRow()[0], "The cursor should follow the selected release")
+	assert.Equal(t, map[string]bool{"data/cache": true, "default/web": true}, model.changed)
+	_, highlighted := model.changedStyle(model.releaseTable.Rows()[1], 0)
+	assert.True(t, highlighted)
+}
+
+// TestRefreshKeepsDetails verifies that a background refresh only lists the
+// releases and that listing the history again keeps the selected revision.
+func TestRefreshKeepsDetails(t *testing.T) {
+	client := newTestClient()
+	client.Histories["data/db"] = []types.History{{Revision: 3}, {Revision: 2}, {Revision: 1}}
+	m := newTestModel(client)
+	updated, _ := m.Update(m.(Model).list())
+	updated, _ = updated.Update(tea.KeyMsg{Type: tea.KeyDown})
+	updated, _ = updated.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	model := updated.(Model)
+	updated, _ = updated.Update(model.history())
+	updated, _ = updated.Update(tea.KeyMsg{Type: tea.KeyDown})
+	assert.Equal(t, "2", updated.(Model).historyTable.SelectedRow()[0])
+
+	client.Calls = nil
+	updated, cmd := updated.Update(updated.(Model).refreshList())
+	assert.Nil(t, cmd, "A refresh should not fetch the details of the selected release")
+	assert.Equal(t, []string{"ListReleases"}, client.Calls)
+
+	client.Histories["data/db"] = append([]types.History{{Revision: 4}}, client.Histories["data/db"]...)
+	model = updated.(Model)
+	updated, _ = updated.Update(model.history())
+	assert.Equal(t, "2", updated.(Model).historyTable.SelectedRow()[0], "The cursor should stay on the selected revision")
+}
+
+// TestRefreshTick verifies that background refreshes only run when an interval is configured.
+func TestRefreshTick(t *testing.T) {
+	assert.Nil(t, refreshTick())
+
+	config.Current.RefreshInterval.Duration = time.Millisecond
+	t.Cleanup(func() { config.Current = config.Default() })
+	assert.Equal(t, refreshMsg{}, refreshTick()())
+}
+
+// TestRefreshWhileInstalling verifies that the refresh keeps ticking while a
+// wizard is open, without listing the releases.
+func TestRefreshWhileInstalling(t *testing.T) {
+	config.Current.RefreshInterval.Duration = time.Millisecond
+	t.Cleanup(func() { config.Current = config.Default() })
+	m := newTestModel(newTestClient()).(Model)
+	m.installing = true
+
+	_, cmd := m.Update(refreshMsg{})
+
+	assert.NotNil(t, cmd)
+	assert.Equal(t, refreshMsg{}, cmd())
+}
+
+// TestFilterReleases verifies that qualified terms match their column and
+// other terms fuzzy-match the name, namespace, status or chart.
+func TestFilterReleases(t *testing.T) {
+	rows := []table.Row{
+		{"web", "default", "1", "", "deployed", "nginx-1.0.0", ""},
+		{"db", "payments", "3", "", "failed", "postgresql-12.0.0", ""},
+		{"api", "payments", "2", "", "deployed", "api-0.1.0", ""},
+	}
+	tests := map[string][]string{
+		"":                          {"web", "db", "api"},
+		"pgsql":                     {"db"},
+		"ngx":                       {"web"},
+		"status:failed":             {"db"},
+		"ns:payments":               {"db", "api"},
+		"status:failed ns:payments": {"db"},
+		"ns:payments DEPLOYED":      {"api"},
+		"status:failed ns:default":  {},
+	}
+	for query, want := range tests {
+		names := []string{}
+		for _, row := range filterReleases(rows, query) {
+			names = append(names, row[0])
+		}
+		assert.Equal(t, want, names, query)
+	}
+}
+
+// TestFilterKeptOnRefresh verifies that the filter narrows the table as it
+// is typed and still applies to the next listing.
+func TestFilterKeptOnRefresh(t *testing.T) {
+	client := newTestClient()
+	m := newTestModel(client)
+	updated, _ := m.Update(m.(Model).list())
+	updated, _ = updated.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("/")})
+	assert.True(t, updated.(Model).InputFocused())
+	for _, r := range "status:failed" {
+		updated, _ = updated.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+	updated, _ = updated.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	assert.Len(t, updated.(Model).releaseTable.Rows(), 1)
+
+	client.Releases = append(client.Releases, types.Release{Name: "cache", Namespace: "data", Revision: "1", Status: "failed"})
+	updated, _ = updated.Update(updated.(Model).list())
+	rows := updated.(Model).releaseTable.Rows()
+	assert.Len(t, rows, 2)
+	assert.Equal(t, "cache", rows[1][0])
+
+	updated, _ = updated.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("/")})
+	updated, _ = updated.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	assert.Len(t, updated.(Model).releaseTable.Rows(), 3, "esc should clear the filter")
+}
+
+// runBatch runs the commands of cmd, batched or not, and returns their
+// messages.
+func runBatch(cmd tea.Cmd) []tea.Msg {
+	if cmd == nil {
+		return nil
+	}
+	msg := cmd()
+	batch, ok := msg.(tea.BatchMsg)
+	if !ok {
+		return []tea.Msg{msg}
+	}
+	var msgs []tea.Msg
+	for _, c := range batch {
+		msgs = append(msgs, runBatch(c)...)
+	}
+	return msgs
+}
+
+// TestFilterHidingSelection verifies that the cursor stays on a listed row
+// when the filter hides the selected release, and that upgrading or opening
+// the details does nothing when no release is listed.
+func TestFilterHidingSelection(t *testing.T) {
+	m := newTestModel(newTestClient())
+	updated, _ := m.Update(m.(Model).list())
+	updated, _ = updated.Update(tea.KeyMsg{Type: tea.KeyDown})
+	filter := func(query string) {
+		updated, _ = updated.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("/")})
+		for _, r := range query {
+			updated, _ = updated.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+		}
+		updated, _ = updated.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	}
+
+	filter("web")
+	assert.Equal(t, "web", updated.(Model).releaseTable.SelectedRow()[0])
+	u := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("u")}
+	upgraded, _ := updated.Update(u)
+	assert.True(t, upgraded.(Model).upgrading)
+	assert.Equal(t, "web", upgraded.(Model).upgradeModel.ReleaseName)
+
+	filter("zzz")
+	assert.Nil(t, updated.(Model).releaseTable.SelectedRow())
+	upgraded, _ = updated.Update(u)
+	assert.False(t, upgraded.(Model).upgrading)
+	opened, _ := updated.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	assert.Equal(t, releasesView, opened.(Model).selectedView)
+}
+
+// TestBulkRollback verifies that a bulk action runs on every marked release
+// once confirmed and reports each failure on its own.
+func TestBulkRollback(t *testing.T) {
+	client := newTestClient()
+	m := newTestModel(client)
+	updated, _ := m.Update(m.(Model).list())
+	updated, _ = updated.Update(tea.KeyMsg{Type: tea.KeySpace, Runes: []rune(" ")})
+	updated, _ = updated.Update(tea.KeyMsg{Type: tea.KeySpace, Runes: []rune(" ")})
+	assert.Equal(t, 2, updated.(Model).markedCount())
+
+	updated, cmd := updated.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("R")})
+	assert.Nil(t, cmd, "Changes should wait for confirmation")
+	assert.True(t, updated.(Model).InputFocused())
+	updated, cmd = updated.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("y")})
+	for _, msg := range runBatch(cmd) {
+		updated, _ = updated.Update(msg)
+	}
+
+	bulk := updated.(Model).bulk
+	assert.True(t, bulk.finished())
+	assert.Equal(t, 1, bulk.failures())
+	assert.EqualError(t, bulk.jobs[0].err, "no previous revision")
+	assert.Equal(t, "rolled back to revision 2", bulk.jobs[1].result)
+	assert.Contains(t, client.Calls, "Rollback db 2 data")
+	assert.Nil(t, updated.(Model).marked)
+
+	updated, _ = updated.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	assert.False(t, updated.(Model).InputFocused())
+}
+
+// TestUpgradeToLatest verifies that a release is upgraded to the latest
+// version of its chart with its current values.
+func TestUpgradeToLatest(t *testing.T) {
+	helpers.UserDir = t.TempDir()
+	client := newTestClient()
+	client.Packages = []types.Pkg{{Name: "bitnami/nginx", Version: "1.2.0"}}
+	client.Values["default/web"] = "replicas: 2\n"
+	wizardFile := filepath.Join(helpers.UserDir, "default", "web", "values.yaml")
+	assert.NoError(t, os.MkdirAll(filepath.Dir(wizardFile), 0755))
+	assert.NoError(t, os.WriteFile(wizardFile, []byte("replicas: 3\n"), 0644))
+
+	result, err := upgradeToLatest(client, table.Row{"web", "default", "1", "", "deployed", "nginx-1.0.0", ""})
+	assert.NoError(t, err)
+	assert.Equal(t, "upgraded to 1.2.0", result)
+	assert.Contains(t, client.Calls[len(client.Calls)-1], "Upgrade web bitnami/nginx 1.2.0 default")
+	assert.FileExists(t, wizardFile, "The folder of the wizards should be left alone")
+
+	_, err = upgradeToLatest(client, table.Row{"db", "data", "3", "", "failed", "postgresql-12.0.0", ""})
+	assert.EqualError(t, err, "chart postgresql not found in the local repositories")
+
+	client.Packages = append(client.Packages, types.Pkg{Name: "other/nginx", Version: "2.0.0"})
+	_, err = upgradeToLatest(client, table.Row{"web", "default", "1", "", "deployed", "nginx-1.0.0", ""})
+	assert.EqualError(t, err, "chart nginx found in several repositories (bitnami/nginx, other/nginx), upgrade the release from the wizard")
+}
+
+// TestStatusSummary verifies that releases are counted by status, pending
+// ones together, deployed, failed and pending first.
+func TestStatusSummary(t *testing.T) {
+	rows := []table.Row{
+		{"a", "", "", "", "superseded"},
+		{"b", "", "", "", "pending-upgrade"},
+		{"c", "", "", "", "deployed"},
+		{"d", "", "", "", "failed"},
+		{"e", "", "", "", "deployed"},
+		{"f", "", "", "", "pending-install"},
+	}
+	assert.Equal(t, "2 deployed, 1 failed, 2 pending, 1 superseded", statusSummary(rows))
+	assert.Equal(t, "", statusSummary(nil))
+}
+
+// TestRevisionDiff verifies that the values and every resource are compared
+// on their own, unchanged resources left out.
+func TestRevisionDiff(t *testing.T) {
+	oldManifest := "---\n# Source: web/templates/svc.yaml\nkind: Service\nmetadata:\n  name: web\n---\nkind: Deployment\nmetadata:\n  name: web\nspec:\n  replicas: 1\n"
+	newManifest := "---\nkind: Deployment\nmetadata:\n  name: web\nspec:\n  replicas: 2\n---\nkind: ConfigMap\nmetadata:\n  name: web\n"
+
+	sections := append([]diffSection{{title: "Values", old: "a: 1\n", new: "a: 1\n"}}, resourceSections(oldManifest, newManifest)...)
+	content, hunks := renderDiff(sections)
+
+	lines := strings.Split(content, "\n")
+	assert.Equal(t, "1 changed, 1 added, 1 removed", lines[0])
+	assert.Equal(t, "━━ Deployment/web (changed)", lines[2])
+	assert.Contains(t, content, "-  replicas: 1\n+  replicas: 2")
+	assert.Contains(t, content, "━━ ConfigMap/web (added)")
+	assert.Contains(t, content, "━━ Service/web (removed)")
+	assert.NotContains(t, content, "Values")
+	assert.Len(t, hunks, 3)
+	assert.True(t, strings.HasPrefix(lines[hunks[0]], "@@ -2,4 +2,4 @@"))
+
+	content, hunks = renderDiff(sections[:1])
+	assert.Equal(t, "No differences", content)
+	assert.Empty(t, hunks)
+}
+
+// TestPickRevisionsToDiff verifies that the diff key pressed on two
+// revisions compares them, oldest first.
+func TestPickRevisionsToDiff(t *testing.T) {
+	client := newTestClient()
+	client.Histories["data/db"] = []types.History{{Revision: 3}, {Revision: 2}, {Revision: 1}}
+	client.Values["data/db@1"] = "a: 1\n"
+	client.Values["data/db@3"] = "a: 3\n"
+	client.Manifests["data/db@1"] = ""
+	client.Manifests["data/db@3"] = ""
+	m := newTestModel(client)
+	updated, _ := m.Update(m.(Model).list())
+	updated, _ = updated.Update(tea.KeyMsg{Type: tea.KeyDown})
+	updated, _ = updated.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	model := updated.(Model)
+	updated, _ = updated.Update(model.history())
+
+	d := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("d")}
+	updated, cmd := updated.Update(d)
+	assert.Nil(t, cmd)
+	assert.Equal(t, 3, updated.(Model).diffBase)
+	updated, _ = updated.Update(tea.KeyMsg{Type: tea.KeyDown})
+	updated, _ = updated.Update(tea.KeyMsg{Type: tea.KeyDown})
+	updated, cmd = updated.Update(d)
+	updated, _ = updated.Update(cmd())
+
+	model = updated.(Model)
+	assert.True(t, model.diffing)
+	assert.Equal(t, 1, model.diffMsg.from)
+	assert.Equal(t, 3, model.diffMsg.to)
+	assert.Contains(t, model.diffMsg.content, "+a: 3")
+
+	updated, _ = updated.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	assert.False(t, updated.(Model).diffing)
+	assert.Equal(t, historyView, updated.(Model).selectedView)
+}
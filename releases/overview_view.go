@@ -5,6 +5,7 @@ import (
 	"strings"
 
 	"github.com/charmbracelet/lipgloss"
+	"github.com/pidanou/helm-tui/components"
 	"github.com/pidanou/helm-tui/helpers"
 	"github.com/pidanou/helm-tui/styles"
 )
@@ -17,10 +18,20 @@ func (m Model) View() string {
 	if m.upgrading {
 		return m.upgradeModel.View()
 	}
+	if m.diffing {
+		return m.renderDiff() + "\n" + m.help.View(diffKeys)
+	}
+	if m.bulk.active() {
+		keys := bulkKeys
+		keys.Confirm.SetEnabled(!m.bulk.started)
+		keys.Cancel.SetEnabled(!m.bulk.started)
+		keys.Back.SetEnabled(m.bulk.finished())
+		return m.renderBulk() + "\n" + m.help.View(keys)
+	}
 	if m.deleting {
-		confirmMsg := "  No release selected. Press n to go back  "
+		confirmMsg := fmt.Sprintf("  No release selected. Press %s to go back  ", deleteKeys.Cancel.Help().Key)
 		if m.releaseTable.SelectedRow() != nil {
-			confirmMsg = fmt.Sprintf("  Delete release %s? y/n  ", m.releaseTable.SelectedRow()[0])
+			confirmMsg = fmt.Sprintf("  Delete release %s? %s/%s  ", m.releaseTable.SelectedRow()[0], deleteKeys.Confirm.Help().Key, deleteKeys.Cancel.Help().Key)
 		}
 		return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, styles.ActiveStyle.Border(styles.Border).Render(confirmMsg))
 	}
@@ -28,14 +39,44 @@ func (m Model) View() string {
 	switch m.selectedView {
 	case releasesView:
 		tHeight := m.height - 2 - 1 // releaseTable padding + helper
+		if m.selectingNamespace {
+			tHeight -= 3
+		}
+		if m.filterShown() {
+			tHeight -= 3
+		}
 		m.releaseTable.SetHeight(tHeight)
 		view = m.renderReleasesTableView()
+		if m.filterShown() {
+			style := styles.InactiveStyle
+			if m.filterInput.Focused() {
+				style = styles.ActiveStyle
+			}
+			view += "\n" + style.Border(styles.Border).Render(m.filterInput.View())
+		}
+		if m.selectingNamespace {
+			view += "\n" + styles.ActiveStyle.Border(styles.Border).Render(m.namespaceInput.View())
+		}
 	default:
 		view = m.renderReleaseDetail()
 	}
 
 	helperStyle := m.help.Styles.ShortSeparator
-	helpView := m.help.View(m.keys[m.selectedView]) + helperStyle.Render(" • ") + m.help.View(helpers.CommonKeys)
+	keys := m.keys[m.selectedView]
+	if m.readOnly() {
+		keys = keys.withoutChanges()
+	}
+	helpView := m.help.View(keys)
+	if m.selectedView == releasesView || m.selectedView == historyView {
+		helpView += helperStyle.Render(" • ") + m.help.View(components.SortKeys)
+	}
+	if m.selectingNamespace {
+		helpView = m.help.View(namespaceKeys)
+	}
+	if m.filterInput.Focused() {
+		helpView = m.help.View(filterKeys)
+	}
+	helpView += helperStyle.Render(" • ") + m.help.View(helpers.CommonKeys)
 	return view + "\n" + helpView
 }
 
@@ -67,6 +108,12 @@ func (m Model) menuView() string {
 	return doc.String()
 }
 
+// filterShown reports whether the filter input is shown under the releases,
+// while typing or as long as a filter is applied.
+func (m Model) filterShown() bool {
+	return m.filterInput.Focused() || m.filterInput.Value() != ""
+}
+
 func (m Model) renderReleaseDetail() string {
 	header := m.renderReleasesTableView() + "\n" + m.menuView()
 	remainingHeight := m.height - lipgloss.Height(header) + lipgloss.Height(m.menuView()) - 2 - 1 // releaseTable padding + helper
@@ -96,16 +143,26 @@ func (m Model) renderReleaseDetail() string {
 
 func (m Model) renderReleasesTableView() string {
 	var releasesTopBorder string
-	tableView := m.releaseTable.View()
+	tableView := components.StyledView(m.releaseTable, m.rowStyle())
 	var baseStyle lipgloss.Style
-	releasesTopBorder = styles.GenerateTopBorderWithTitle(" Releases ", m.releaseTable.Width(), styles.Border, styles.InactiveStyle)
+	title := fmt.Sprintf(" Releases (%s) ", scopeLabel(m.namespaces))
+	if m.selectedView == releasesView && m.filterInput.Value() != "" {
+		title = fmt.Sprintf(" Releases (%s) %d/%d ", scopeLabel(m.namespaces), len(m.releaseTable.Rows()), len(m.releases))
+	}
+	if summary := statusSummary(m.releaseTable.Rows()); m.selectedView == releasesView && summary != "" {
+		title += "· " + summary + " "
+	}
+	if marked := m.markedCount(); m.selectedView == releasesView && marked > 0 {
+		title += fmt.Sprintf("· %d marked ", marked)
+	}
+	releasesTopBorder = styles.GenerateTopBorderWithTitle(title, m.releaseTable.Width(), styles.Border, styles.InactiveStyle)
 	baseStyle = styles.InactiveStyle.Border(styles.Border, false, true, true)
 	tableView = baseStyle.Render(tableView)
 	return lipgloss.JoinVertical(lipgloss.Top, releasesTopBorder, tableView)
 }
 
 func (m Model) renderHistoryTableView() string {
-	tableView := m.historyTable.View()
+	tableView := components.StyledView(m.historyTable, m.historyStyle())
 	var baseStyle lipgloss.Style
 	baseStyle = styles.InactiveStyle.Border(styles.Border).UnsetBorderTop()
 	tableView = baseStyle.Render(tableView)
@@ -0,0 +1,119 @@
+package releases
+
+import (
+	"time"
+
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/pidanou/helm-tui/components"
+	"github.com/pidanou/helm-tui/config"
+	"github.com/pidanou/helm-tui/styles"
+	"github.com/pidanou/helm-tui/types"
+)
+
+// refreshMsg lists the releases again in the background.
+type refreshMsg struct{}
+
+// refreshTick schedules the next background listing, none when the refresh
+// interval is not configured.
+func refreshTick() tea.Cmd {
+	interval := config.Current.RefreshInterval.Duration
+	if interval <= 0 {
+		return nil
+	}
+	return tea.Tick(interval, func(time.Time) tea.Msg {
+		return refreshMsg{}
+	})
+}
+
+// refreshedMsg is a background listing of the releases, which leaves the
+// details of the selected release as they are.
+type refreshedMsg types.ListReleasesMsg
+
+func (m Model) refreshList() tea.Msg {
+	return refreshedMsg(m.list().(types.ListReleasesMsg))
+}
+
+// setReleases keeps the releases of a listing and shows the ones passing the
+// filter, the selection kept.
+func (m *Model) setReleases(msg types.ListReleasesMsg) {
+	for _, row := range msg.Content {
+		m.knownNamespaces = mergeNamespaces(m.knownNamespaces, row[1])
+	}
+	if msg.Err == nil {
+		m.trackChanges(msg.Content)
+	}
+	m.releases = msg.Content
+	if m.selectedView == releasesView {
+		m.applyFilter(&m.releaseTable)
+	} else {
+		m.applyFilter(&releaseTableCache)
+	}
+}
+
+// setHistory lists the revisions of the selected release, the cursor staying
+// on the same revision when the history of that release is listed again.
+func (m *Model) setHistory(rows []table.Row) {
+	revision := ""
+	release := releaseKey(m.releaseTable.SelectedRow())
+	if release == m.historyOf && m.historyTable.SelectedRow() != nil {
+		revision = m.historyTable.SelectedRow()[0]
+	}
+	m.historyOf = release
+	m.historyTable.SetRows(rows)
+	components.Sort(&m.historyTable, "history", historyCols)
+	m.historyTable.SetCursor(0)
+	for i, row := range m.historyTable.Rows() {
+		if row[0] == revision {
+			m.historyTable.SetCursor(i)
+		}
+	}
+}
+
+// releaseKey identifies the release of a row as namespace/name.
+func releaseKey(row table.Row) string {
+	if len(row) < 2 {
+		return ""
+	}
+	return row[1] + "/" + row[0]
+}
+
+// setRowsKeepingSelection replaces the rows of t, keeping the cursor on the
+// same release, or within the rows when that release is gone.
+func setRowsKeepingSelection(t *table.Model, rows []table.Row) {
+	selected := releaseKey(t.SelectedRow())
+	t.SetRows(rows)
+	for i, row := range rows {
+		if releaseKey(row) == selected {
+			t.SetCursor(i)
+			return
+		}
+	}
+	t.SetCursor(min(t.Cursor(), len(rows)-1))
+}
+
+// trackChanges marks the releases whose revision or status changed since the
+// previous listing, or that were not listed then.
+func (m *Model) trackChanges(rows []table.Row) {
+	revisions := make(map[string]string, len(rows))
+	changed := map[string]bool{}
+	for _, row := range rows {
+		key := releaseKey(row)
+		revisions[key] = row[2] + " " + row[4]
+		if m.revisions != nil && m.revisions[key] != revisions[key] {
+			changed[key] = true
+		}
+	}
+	m.revisions = revisions
+	m.changed = changed
+}
+
+// changedStyle highlights the rows of the releases changed by the last
+// listing.
+func (m Model) changedStyle(row table.Row, _ int) (lipgloss.Style, bool) {
+	if !m.changed[releaseKey(row)] {
+		return lipgloss.Style{}, false
+	}
+	return lipgloss.NewStyle().Foreground(styles.CurrentTheme.Changed).Bold(true), true
+}
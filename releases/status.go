@@ -0,0 +1,108 @@
+package releases
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/table"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/pidanou/helm-tui/styles"
+)
+
+// statusGlyphs tell the release statuses apart without colors.
+var statusGlyphs = map[string]string{
+	"deployed":     "✓",
+	"failed":       "✗",
+	"pending":      "◷",
+	"superseded":   "○",
+	"uninstalling": "−",
+	"uninstalled":  "−",
+}
+
+// statusGroup folds pending-install, pending-upgrade and pending-rollback
+// into pending.
+func statusGroup(status string) string {
+	if strings.HasPrefix(status, "pending") {
+		return "pending"
+	}
+	return status
+}
+
+// statusStyle colors a status and prefixes it with its glyph.
+func statusStyle(status string) lipgloss.Style {
+	glyph, ok := statusGlyphs[statusGroup(status)]
+	if !ok {
+		glyph = "?"
+	}
+	style := lipgloss.NewStyle().Transform(func(s string) string {
+		return glyph + " " + s
+	})
+	switch statusGroup(status) {
+	case "deployed":
+		return style.Foreground(styles.CurrentTheme.Success)
+	case "failed":
+		return style.Foreground(styles.CurrentTheme.Error)
+	case "pending":
+		return style.Foreground(styles.CurrentTheme.Pending)
+	case "superseded", "uninstalling", "uninstalled":
+		return style.Foreground(styles.CurrentTheme.Subtle)
+	}
+	return style
+}
+
+// statusColumn styles the status cells of a table whose status is column.
+func statusColumn(column int) func(table.Row, int) (lipgloss.Style, bool) {
+	return func(row table.Row, col int) (lipgloss.Style, bool) {
+		if col != column || col >= len(row) {
+			return lipgloss.Style{}, false
+		}
+		return statusStyle(row[col]), true
+	}
+}
+
+// statusSummary counts the releases of rows by status, such as
+// "42 deployed, 2 failed, 1 pending", deployed, failed and pending first.
+func statusSummary(rows []table.Row) string {
+	counts := map[string]int{}
+	for _, row := range rows {
+		counts[statusGroup(row[4])]++
+	}
+	order := map[string]int{"deployed": 1, "failed": 2, "pending": 3}
+	statuses := make([]string, 0, len(counts))
+	for status := range counts {
+		statuses = append(statuses, status)
+	}
+	sort.Slice(statuses, func(i, j int) bool {
+		oi, oj := order[statuses[i]], order[statuses[j]]
+		if oi == 0 {
+			oi = len(order) + 1
+		}
+		if oj == 0 {
+			oj = len(order) + 1
+		}
+		if oi != oj {
+			return oi < oj
+		}
+		return statuses[i] < statuses[j]
+	})
+	parts := make([]string, 0, len(statuses))
+	for _, status := range statuses {
+		parts = append(parts, fmt.Sprintf("%d %s", counts[status], status))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// historyStyle highlights the revision picked to be compared and colors the
+// statuses of the other ones.
+func (m Model) historyStyle() func(table.Row, int) (lipgloss.Style, bool) {
+	status := statusColumn(2)
+	return func(row table.Row, col int) (lipgloss.Style, bool) {
+		style, ok := status(row, col)
+		if m.diffBase != 0 && row[0] == strconv.Itoa(m.diffBase) {
+			return lipgloss.NewStyle().Foreground(styles.CurrentTheme.Highlight).Underline(true).Transform(style.GetTransform()), true
+		}
+		return style, ok
+	}
+}
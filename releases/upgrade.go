@@ -1,12 +1,18 @@
 package releases
 
 import (
+	"errors"
 	"fmt"
 	"time"
 
 	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/pidanou/helm-tui/components"
+	"github.com/pidanou/helm-tui/config"
+	"github.com/pidanou/helm-tui/helm"
 	"github.com/pidanou/helm-tui/helpers"
 	"github.com/pidanou/helm-tui/types"
 )
@@ -14,18 +20,25 @@ import (
 const (
 	upgradeReleaseChartStep int = iota
 	upgradeReleaseVersionStep
+	upgradeReleaseValuesFilesStep
 	upgradeReleaseValuesStep
+	upgradeReleaseOverridesStep
+	upgradeReleaseOptionsStep
 	upgradeReleaseConfirmStep
 )
 
 var upgradeInputsHelper = []string{
 	"Enter a chart name or chart directory (absolute path)",
 	"Version (empty for latest)",
+	"Add values file (empty to continue)",
 	"Edit values yes/no/use default ? y/n/d",
-	"Confirm ? enter/esc",
+	"Add %s key=value (empty to continue)",
+	"Upgrade options (enter to continue)",
+	"Preview changes ? enter/esc",
 }
 
 type UpgradeModel struct {
+	client      helm.HelmClient
 	ReleaseName string
 	Namespace   string
 	upgradeStep int
@@ -37,17 +50,43 @@ type UpgradeModel struct {
 	help        help.Model
 	keys        keyMap
 	tag         int
+	// previewing shows the changes of the upgrade, as rendered by a dry run,
+	// until it is confirmed.
+	previewing bool
+	preview    upgradePreviewMsg
+	previewVP  viewport.Model
+	// invalid is why the values just edited were refused, until they are
+	// edited again or the wizard is aborted.
+	invalid *helpers.YAMLError
+	// violations are the edited values breaking the schema of the chart,
+	// shown until they are edited again or esc goes back to the values step.
+	violations   []helm.Violation
+	violationsVP viewport.Model
+	// valuesFiles are local values files passed to helm, in order, before
+	// the edited values.
+	valuesFiles components.ValuesFiles
+	// overrides are the key=value overrides of the values, passed with
+	// --set, --set-string or --set-json.
+	overrides components.Overrides
+	// options are the flags of helm upgrade, starting from the defaults of
+	// the namespace.
+	options upgradeOptions
 }
 
-func InitUpgradeModel() UpgradeModel {
+func InitUpgradeModel(client helm.HelmClient) UpgradeModel {
 	chart := textinput.New()
 	version := textinput.New()
+	valuesFiles := textinput.New()
 	value := textinput.New()
+	overrides := textinput.New()
+	options := textinput.New()
 	confirm := textinput.New()
-	inputs := []textinput.Model{chart, version, value, confirm}
-	m := UpgradeModel{upgradeStep: upgradeReleaseChartStep, Inputs: inputs, help: help.New(), keys: upgradeKeys}
+	inputs := []textinput.Model{chart, version, valuesFiles, value, overrides, options, confirm}
+	m := UpgradeModel{client: client, upgradeStep: upgradeReleaseChartStep, Inputs: inputs, help: help.New(), keys: upgradeKeys, valuesFiles: components.NewValuesFiles(), overrides: components.NewOverrides(), options: newUpgradeOptions(config.UpgradeDefaults{})}
 	m.Inputs[upgradeReleaseChartStep].ShowSuggestions = true
 	m.Inputs[upgradeReleaseVersionStep].ShowSuggestions = true
+	m.Inputs[upgradeReleaseValuesFilesStep].ShowSuggestions = true
+	m.Inputs[upgradeReleaseOverridesStep].ShowSuggestions = true
 	return m
 }
 
@@ -60,10 +99,25 @@ func (m UpgradeModel) Update(msg tea.Msg) (UpgradeModel, tea.Cmd) {
 	cmds := make([]tea.Cmd, len(m.Inputs))
 	switch msg := msg.(type) {
 	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
 		m.Inputs[upgradeReleaseChartStep].Width = msg.Width - 6 - len(upgradeInputsHelper[0])
+		m.Inputs[upgradeReleaseValuesFilesStep].Width = msg.Width - 6 - len(upgradeInputsHelper[upgradeReleaseValuesFilesStep])
 		m.Inputs[upgradeReleaseValuesStep].Width = msg.Width - 6 - len(upgradeInputsHelper[1])
+	case upgradePreviewMsg:
+		m.previewing = true
+		m.preview = msg
+		m.previewVP = viewport.New(m.width-2, m.height-3) // borders and help
+		m.previewVP.SetContent(detailContent(msg.content, msg.err))
+		return m, nil
+	case components.ValuePathsMsg:
+		m.Inputs[upgradeReleaseOverridesStep].SetSuggestions(msg.Paths)
+		return m, nil
 	case types.UpgradeMsg:
+		m.previewing = false
 		m.upgradeStep = 0
+		m.valuesFiles.Reset()
+		m.overrides.Reset()
 		if m.Namespace == "" {
 			m.Namespace = "default"
 		}
@@ -79,27 +133,92 @@ func (m UpgradeModel) Update(msg tea.Msg) (UpgradeModel, tea.Cmd) {
 			if m.Inputs[upgradeReleaseVersionStep].Focused() {
 				m.Inputs[upgradeReleaseVersionStep].SetSuggestions(m.searchLocalPackageVersion())
 			}
+			if m.Inputs[upgradeReleaseValuesFilesStep].Focused() {
+				m.Inputs[upgradeReleaseValuesFilesStep].SetSuggestions(components.PathSuggestions(m.Inputs[upgradeReleaseValuesFilesStep].Value()))
+			}
 		}
 	case types.EditorFinishedMsg:
-		m.upgradeStep++
-		for i := 0; i <= len(m.Inputs)-1; i++ {
-			if i == int(m.upgradeStep) {
-				cmds[i] = m.Inputs[i].Focus()
-				continue
-			}
-			m.Inputs[i].Blur()
+		if errors.As(msg.Invalid, &m.invalid) {
+			return m, nil
 		}
-		return m, tea.Batch(cmds...)
+		m.invalid = nil
+		if msg.Err == nil {
+			return m, components.CheckValues(m.client, m.Inputs[upgradeReleaseChartStep].Value(), m.Inputs[upgradeReleaseVersionStep].Value(), m.valueSources())
+		}
+		return m, m.nextStep()
+	case components.SchemaMsg:
+		if msg.Err != nil {
+			helpers.Println("checking values against the chart schema:", msg.Err)
+		}
+		if len(msg.Violations) > 0 {
+			m.violations = msg.Violations
+			m.violationsVP = components.ViolationsViewport(m.width, m.height, msg.Violations)
+			return m, nil
+		}
+		return m, m.nextStep()
 	case tea.KeyMsg:
 		m.tag++
-		switch msg.String() {
-		case "enter":
+		if m.invalid != nil {
+			switch {
+			case key.Matches(msg, m.keys.Next):
+				cmd = helpers.OpenFileAt(m.invalid.File, m.invalid.Line, 0)
+				m.invalid = nil
+				return m, cmd
+			case key.Matches(msg, m.keys.Cancel):
+				m.invalid = nil
+			default:
+				return m, nil
+			}
+		}
+		if m.violations != nil {
+			switch {
+			case key.Matches(msg, m.keys.Next):
+				line := components.FirstViolationLine(m.violations)
+				m.violations = nil
+				return m, helpers.OpenFileAt(m.valuesFile(), line, 0)
+			case key.Matches(msg, m.keys.Cancel):
+				m.violations = nil
+			default:
+				m.violationsVP, cmd = m.violationsVP.Update(msg)
+			}
+			return m, cmd
+		}
+		if m.previewing {
+			switch {
+			case key.Matches(msg, m.keys.Next):
+				if m.preview.err == nil {
+					m.previewing = false
+					m.upgradeStep = 0
+					cmd = m.blurAllInputs()
+					cmds = append(cmds, cmd, m.upgrade)
+					return m, tea.Batch(cmds...)
+				}
+			case key.Matches(msg, diffKeys.Next):
+				components.JumpTo(&m.previewVP, m.preview.hunks, true)
+			case key.Matches(msg, diffKeys.Previous):
+				components.JumpTo(&m.previewVP, m.preview.hunks, false)
+			case key.Matches(msg, m.keys.Cancel):
+				// back to the confirmation, the steps kept
+				m.previewing = false
+			default:
+				m.previewVP, cmd = m.previewVP.Update(msg)
+				return m, cmd
+			}
+			return m, nil
+		}
+		if m.upgradeStep == upgradeReleaseValuesFilesStep && m.valuesFiles.Update(&m.Inputs[upgradeReleaseValuesFilesStep], msg) {
+			return m, nil
+		}
+		if m.upgradeStep == upgradeReleaseOverridesStep && m.overrides.Update(&m.Inputs[upgradeReleaseOverridesStep], msg) {
+			return m, nil
+		}
+		if m.upgradeStep == upgradeReleaseOptionsStep && !key.Matches(msg, m.keys.Next, m.keys.Cancel) {
+			return m, m.options.Update(msg)
+		}
+		switch {
+		case key.Matches(msg, m.keys.Next):
 			if m.upgradeStep == upgradeReleaseConfirmStep {
-				m.upgradeStep = 0
-				cmd = m.blurAllInputs()
-				cmds = append(cmds, cmd, m.upgrade)
-
-				return m, tea.Batch(cmds...)
+				return m, m.previewUpgrade
 			}
 
 			if m.upgradeStep == upgradeReleaseValuesStep {
@@ -116,19 +235,30 @@ func (m UpgradeModel) Update(msg tea.Msg) (UpgradeModel, tea.Cmd) {
 				}
 			}
 
-			m.upgradeStep++
-
-			for i := 0; i <= len(m.Inputs)-1; i++ {
-				if i == int(m.upgradeStep) {
-					cmds[i] = m.Inputs[i].Focus()
-					continue
+			if m.upgradeStep == upgradeReleaseValuesFilesStep && m.Inputs[upgradeReleaseValuesFilesStep].Value() != "" {
+				if m.valuesFiles.Add(m.Inputs[upgradeReleaseValuesFilesStep].Value()) == nil {
+					m.Inputs[upgradeReleaseValuesFilesStep].SetValue("")
 				}
-				m.Inputs[i].Blur()
+				return m, nil
+			}
+			if m.upgradeStep == upgradeReleaseOverridesStep && m.Inputs[upgradeReleaseOverridesStep].Value() != "" {
+				if m.overrides.Add(m.Inputs[upgradeReleaseOverridesStep].Value()) {
+					m.Inputs[upgradeReleaseOverridesStep].SetValue("")
+				}
+				return m, nil
+			}
+			if m.upgradeStep == upgradeReleaseOptionsStep {
+				if m.options.validate() != nil {
+					return m, nil
+				}
+				m.options.blur()
 			}
 
-			return m, tea.Batch(cmds...)
-		case "esc":
+			return m, m.nextStep()
+		case key.Matches(msg, m.keys.Cancel):
 			m.upgradeStep = 0
+			m.valuesFiles.Reset()
+			m.overrides.Reset()
 			for i := 0; i <= len(m.Inputs)-1; i++ {
 				m.Inputs[i].Blur()
 				m.Inputs[i].SetValue("")
@@ -143,3 +273,29 @@ func (m UpgradeModel) Update(msg tea.Msg) (UpgradeModel, tea.Cmd) {
 	cmd = m.updateInputs(msg)
 	return m, cmd
 }
+
+// nextStep focuses the input of the step after the current one.
+func (m *UpgradeModel) nextStep() tea.Cmd {
+	m.upgradeStep++
+	cmds := make([]tea.Cmd, len(m.Inputs))
+	for i := range m.Inputs {
+		if i == int(m.upgradeStep) {
+			cmds[i] = m.Inputs[i].Focus()
+			continue
+		}
+		m.Inputs[i].Blur()
+	}
+	switch m.upgradeStep {
+	case upgradeReleaseOverridesStep:
+		cmds = append(cmds, components.FetchValuePaths(m.client, m.Inputs[upgradeReleaseChartStep].Value(), m.Inputs[upgradeReleaseVersionStep].Value()))
+	case upgradeReleaseOptionsStep:
+		m.options = newUpgradeOptions(config.Current.UpgradeDefaultsIn(m.Namespace))
+	}
+	return tea.Batch(cmds...)
+}
+
+// backOnCancel reports whether esc goes back to a step of the wizard
+// instead of closing it.
+func (m UpgradeModel) backOnCancel() bool {
+	return m.previewing || m.violations != nil
+}
@@ -1,13 +1,11 @@
 package releases
 
 import (
-	"bytes"
-	"encoding/json"
 	"fmt"
 	"os"
-	"os/exec"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/pidanou/helm-tui/helm"
 	"github.com/pidanou/helm-tui/helpers"
 	"github.com/pidanou/helm-tui/types"
 )
@@ -37,20 +35,68 @@ func (m UpgradeModel) resetAllInputs() tea.Cmd {
 	return nil
 }
 
+// upgradePreviewMsg holds the diff between the manifest of a release and
+// the one its upgrade renders.
+type upgradePreviewMsg struct {
+	content string
+	hunks   []int
+	err     error
+}
+
+// valuesFile is where the values of the release are edited, "" when they
+// are not.
+func (m UpgradeModel) valuesFile() string {
+	if m.Inputs[upgradeReleaseValuesStep].Value() != "y" && m.Inputs[upgradeReleaseValuesStep].Value() != "d" {
+		return ""
+	}
+	return fmt.Sprintf("%s/%s/%s/values.yaml", helpers.UserDir, m.Namespace, m.ReleaseName)
+}
+
+// valueSources returns the values the upgrade gives to helm.
+func (m UpgradeModel) valueSources() helm.ValueSources {
+	set, setString, setJSON := m.overrides.Flags()
+	return helm.ValueSources{Files: m.valuesFiles.Paths, File: m.valuesFile(), Set: set, SetString: setString, SetJSON: setJSON}
+}
+
+// upgradeOptions returns the options of the upgrade, and why the options of
+// the checklist are refused.
+func (m UpgradeModel) upgradeOptions() (helm.UpgradeOptions, error) {
+	opts := helm.UpgradeOptions{
+		ReleaseName: m.ReleaseName,
+		Chart:       m.Inputs[upgradeReleaseChartStep].Value(),
+		Version:     m.Inputs[upgradeReleaseVersionStep].Value(),
+		Namespace:   m.Namespace,
+		ValuesFiles: m.valuesFiles.Paths,
+		ValuesFile:  m.valuesFile(),
+	}
+	opts.Set, opts.SetString, opts.SetJSON = m.overrides.Flags()
+	return opts, m.options.apply(&opts)
+}
+
+// previewUpgrade renders the upgrade with a dry run and compares its
+// manifest with the one of the release.
+func (m UpgradeModel) previewUpgrade() tea.Msg {
+	opts, err := m.upgradeOptions()
+	if err != nil {
+		return upgradePreviewMsg{err: err}
+	}
+	preview, err := m.client.PreviewUpgrade(opts)
+	if err != nil {
+		return upgradePreviewMsg{err: err}
+	}
+	current, err := m.client.GetManifest(m.ReleaseName, m.Namespace)
+	if err != nil {
+		return upgradePreviewMsg{err: err}
+	}
+	content, hunks := renderDiff(resourceSections(current, preview.Manifest))
+	return upgradePreviewMsg{content: content, hunks: hunks}
+}
+
 func (m UpgradeModel) upgrade() tea.Msg {
-	folder := fmt.Sprintf("%s/%s/%s", helpers.UserDir, m.Namespace, m.ReleaseName)
-	_ = os.MkdirAll(folder, 0755)
-	file := fmt.Sprintf("%s/values.yaml", folder)
-	var cmd *exec.Cmd
-	if m.Inputs[upgradeReleaseValuesStep].Value() == "y" || m.Inputs[upgradeReleaseValuesStep].Value() == "d" {
-		cmd = exec.Command("helm", "upgrade", m.ReleaseName, m.Inputs[upgradeReleaseChartStep].Value(), "--values", file, "--namespace", m.Namespace)
-	} else {
-		cmd = exec.Command("helm", "upgrade", m.ReleaseName, m.Inputs[upgradeReleaseChartStep].Value(), "--namespace", m.Namespace)
+	opts, err := m.upgradeOptions()
+	if err == nil {
+		err = m.client.Upgrade(opts)
 	}
-	var stderr bytes.Buffer
-	cmd.Stderr = &stderr
-	// Run the command
-	err := cmd.Run()
 	if err != nil {
 		return types.UpgradeMsg{Err: err}
 	}
@@ -58,41 +104,30 @@ func (m UpgradeModel) upgrade() tea.Msg {
 }
 
 func (m UpgradeModel) openEditorWithValues(defaultValues bool) tea.Cmd {
-	var stdout, stderr bytes.Buffer
 	folder := fmt.Sprintf("%s/%s/%s", helpers.UserDir, m.Namespace, m.ReleaseName)
 	_ = os.MkdirAll(folder, 0755)
 	file := fmt.Sprintf("%s/values.yaml", folder)
 	packageName := m.Inputs[upgradeReleaseChartStep].Value()
 	version := m.Inputs[upgradeReleaseVersionStep].Value()
 
-	var cmd *exec.Cmd
+	var values string
+	var err error
 	if defaultValues {
-		cmd = exec.Command("helm", "show", "values", packageName, "--version", version)
+		values, err = m.client.ShowValues(packageName, version)
 	} else {
-		cmd = exec.Command("helm", "get", "values", m.ReleaseName, "--namespace", m.Namespace)
+		values, err = m.client.GetValues(m.ReleaseName, m.Namespace)
 	}
-	cmd.Stdout = &stdout
-	cmd.Stderr = &stderr
-	err := cmd.Run()
 	if err != nil {
 		return func() tea.Msg { return types.EditorFinishedMsg{Err: err} }
 	}
-	return helpers.WriteAndOpenFile(stdout.Bytes(), file)
+	return helpers.WriteAndOpenFile([]byte(m.valuesFiles.Seed(values)), file)
 }
 
 func (m UpgradeModel) searchLocalPackage() []string {
 	if m.Inputs[upgradeReleaseChartStep].Value() == "" {
 		return []string{}
 	}
-	var stdout bytes.Buffer
-	cmd := exec.Command("helm", "search", "repo", m.Inputs[upgradeReleaseChartStep].Value(), "--output", "json")
-	cmd.Stdout = &stdout
-	err := cmd.Run()
-	if err != nil {
-		return []string{}
-	}
-	var pkgs []types.Pkg
-	err = json.Unmarshal(stdout.Bytes(), &pkgs)
+	pkgs, err := m.client.SearchRepo(helm.SearchOptions{Keyword: m.Inputs[upgradeReleaseChartStep].Value()})
 	if err != nil {
 		return []string{}
 	}
@@ -105,15 +140,7 @@ func (m UpgradeModel) searchLocalPackage() []string {
 }
 
 func (m UpgradeModel) searchLocalPackageVersion() []string {
-	var stdout bytes.Buffer
-	cmd := exec.Command("helm", "search", "repo", "--regexp", "\v"+m.Inputs[upgradeReleaseChartStep].Value()+"\v", "--versions", "--output", "json")
-	cmd.Stdout = &stdout
-	err := cmd.Run()
-	if err != nil {
-		return []string{}
-	}
-	var pkgs []types.Pkg
-	err = json.Unmarshal(stdout.Bytes(), &pkgs)
+	pkgs, err := m.client.SearchRepo(helm.SearchOptions{Keyword: "\v" + m.Inputs[upgradeReleaseChartStep].Value() + "\v", Regexp: true, Versions: true})
 	if err != nil {
 		return []string{}
 	}
@@ -1,7 +1,41 @@
 package releases
 
-import "github.com/charmbracelet/bubbles/key"
+import (
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/pidanou/helm-tui/helpers"
+)
 
 var upgradeKeys = keyMap{
+	Next:   key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "Next")),
 	Cancel: key.NewBinding(key.WithKeys("esc"), key.WithHelp("esc", "Cancel")),
 }
+
+type upgradeOptionsKeyMap struct {
+	Up     key.Binding
+	Down   key.Binding
+	Toggle key.Binding
+}
+
+// ShortHelp returns keybindings to be shown in the mini help view. It's part
+// of the key.Map interface.
+func (k upgradeOptionsKeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{k.Up, k.Down, k.Toggle}
+}
+
+// FullHelp returns keybindings for the expanded help view. It's part of the
+// key.Map interface.
+func (k upgradeOptionsKeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{}
+}
+
+// upgradeOptionsKeys move through the checklist of upgrade options.
+var upgradeOptionsKeys = upgradeOptionsKeyMap{
+	Up:     key.NewBinding(key.WithKeys("up"), key.WithHelp("↑", "Previous option")),
+	Down:   key.NewBinding(key.WithKeys("down"), key.WithHelp("↓", "Next option")),
+	Toggle: key.NewBinding(key.WithKeys(" "), key.WithHelp("space", "Toggle")),
+}
+
+func init() {
+	helpers.RegisterKeys("releases.upgrade", map[string]*key.Binding{"next": &upgradeKeys.Next, "cancel": &upgradeKeys.Cancel}, "global")
+	helpers.RegisterKeys("releases.upgrade.options", map[string]*key.Binding{"up": &upgradeOptionsKeys.Up, "down": &upgradeOptionsKeys.Down, "toggle": &upgradeOptionsKeys.Toggle}, "global", "releases.upgrade")
+}
@@ -0,0 +1,201 @@
+package releases
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/pidanou/helm-tui/config"
+	"github.com/pidanou/helm-tui/helm"
+	"github.com/pidanou/helm-tui/styles"
+)
+
+// upgradeOption is a row of the checklist of upgrade options.
+type upgradeOption int
+
+const (
+	optionAtomic upgradeOption = iota
+	optionWait
+	optionWaitForJobs
+	optionForce
+	optionCleanupOnFail
+	optionValues
+	optionTimeout
+	optionHistoryMax
+	optionDescription
+	optionCount
+)
+
+var upgradeOptionLabels = []string{
+	"--atomic, roll back on failure",
+	"--wait, until resources are ready",
+	"--wait-for-jobs, until jobs complete",
+	"--force, replace resources",
+	"--cleanup-on-fail, delete new resources on failure",
+	"Values",
+	"--timeout",
+	"--history-max",
+	"--description",
+}
+
+// upgradeOptions is the checklist of the flags of helm upgrade, starting
+// from the defaults of the namespace.
+type upgradeOptions struct {
+	checked [optionValues]bool
+	// values is the index of the values strategy in helm.ValuesStrategies,
+	// -1 for helm's default.
+	values int
+	// inputs edit the timeout, the history max and the description.
+	inputs [optionCount - optionTimeout]textinput.Model
+	cursor upgradeOption
+	// err is why the options typed were refused.
+	err error
+}
+
+func newUpgradeOptions(d config.UpgradeDefaults) upgradeOptions {
+	o := upgradeOptions{values: -1}
+	o.checked = [optionValues]bool{d.Atomic, d.Wait, d.WaitForJobs, d.Force, d.CleanupOnFail}
+	for i, strategy := range helm.ValuesStrategies {
+		if string(strategy) == d.Values {
+			o.values = i
+		}
+	}
+	for i := range o.inputs {
+		o.inputs[i] = textinput.New()
+		o.inputs[i].Prompt = ""
+	}
+	o.input(optionTimeout).Placeholder = "5m0s"
+	o.input(optionHistoryMax).Placeholder = "10"
+	if d.Timeout.Duration != 0 {
+		o.input(optionTimeout).SetValue(d.Timeout.String())
+	}
+	if d.HistoryMax != 0 {
+		o.input(optionHistoryMax).SetValue(strconv.Itoa(d.HistoryMax))
+	}
+	o.input(optionDescription).SetValue(d.Description)
+	return o
+}
+
+// input is the input editing option, one of the text options.
+func (o *upgradeOptions) input(option upgradeOption) *textinput.Model {
+	return &o.inputs[option-optionTimeout]
+}
+
+// Update moves through the checklist, toggles the options and edits the
+// text ones.
+func (o *upgradeOptions) Update(msg tea.KeyMsg) tea.Cmd {
+	var cmd tea.Cmd
+	switch {
+	case key.Matches(msg, upgradeOptionsKeys.Up):
+		o.cursor = (o.cursor + optionCount - 1) % optionCount
+		return o.focus()
+	case key.Matches(msg, upgradeOptionsKeys.Down):
+		o.cursor = (o.cursor + 1) % optionCount
+		return o.focus()
+	case o.cursor >= optionTimeout:
+		input := o.input(o.cursor)
+		*input, cmd = input.Update(msg)
+	case key.Matches(msg, upgradeOptionsKeys.Toggle) && o.cursor == optionValues:
+		o.values++
+		if o.values == len(helm.ValuesStrategies) {
+			o.values = -1
+		}
+	case key.Matches(msg, upgradeOptionsKeys.Toggle):
+		o.checked[o.cursor] = !o.checked[o.cursor]
+	}
+	return cmd
+}
+
+// focus focuses the input of the row under the cursor, if any.
+func (o *upgradeOptions) focus() tea.Cmd {
+	var cmd tea.Cmd
+	for i := range o.inputs {
+		if upgradeOption(i)+optionTimeout == o.cursor {
+			cmd = o.inputs[i].Focus()
+			o.inputs[i].CursorEnd()
+			continue
+		}
+		o.inputs[i].Blur()
+	}
+	return cmd
+}
+
+// blur leaves the checklist without any input focused.
+func (o *upgradeOptions) blur() {
+	for i := range o.inputs {
+		o.inputs[i].Blur()
+	}
+}
+
+// validate checks the timeout and the history max typed, keeping why they
+// are refused for View.
+func (o *upgradeOptions) validate() error {
+	o.err = o.apply(&helm.UpgradeOptions{})
+	return o.err
+}
+
+// apply sets the flags of opts from the checklist.
+func (o upgradeOptions) apply(opts *helm.UpgradeOptions) error {
+	opts.Atomic, opts.Wait, opts.WaitForJobs = o.checked[optionAtomic], o.checked[optionWait], o.checked[optionWaitForJobs]
+	opts.Force, opts.CleanupOnFail = o.checked[optionForce], o.checked[optionCleanupOnFail]
+	if o.values >= 0 {
+		opts.Values = helm.ValuesStrategies[o.values]
+	}
+	opts.Description = strings.TrimSpace(o.input(optionDescription).Value())
+	if timeout := strings.TrimSpace(o.input(optionTimeout).Value()); timeout != "" {
+		d, err := time.ParseDuration(timeout)
+		if err != nil || d <= 0 {
+			return fmt.Errorf("--timeout must be a duration such as 5m, not %q", timeout)
+		}
+		opts.Timeout = d
+	}
+	if historyMax := strings.TrimSpace(o.input(optionHistoryMax).Value()); historyMax != "" {
+		n, err := strconv.Atoi(historyMax)
+		if err != nil || n < 0 {
+			return fmt.Errorf("--history-max must be a number of revisions, not %q", historyMax)
+		}
+		opts.HistoryMax = n
+	}
+	return nil
+}
+
+// View lists the options, the one under the cursor highlighted when focused
+// is set.
+func (o upgradeOptions) View(focused bool) string {
+	highlight := lipgloss.NewStyle().Foreground(styles.CurrentTheme.Highlight)
+	lines := make([]string, 0, optionCount+1)
+	for option := upgradeOption(0); option < optionCount; option++ {
+		var line string
+		switch {
+		case option < optionValues:
+			check := " "
+			if o.checked[option] {
+				check = "x"
+			}
+			line = fmt.Sprintf("[%s] %s", check, upgradeOptionLabels[option])
+		case option == optionValues:
+			values := "helm's default"
+			if o.values >= 0 {
+				values = "--" + string(helm.ValuesStrategies[o.values]) + "-values"
+			}
+			line = fmt.Sprintf("%s: %s", upgradeOptionLabels[option], values)
+		default:
+			line = fmt.Sprintf("%s: %s", upgradeOptionLabels[option], o.input(option).View())
+		}
+		if focused && option == o.cursor {
+			line = highlight.Render("> ") + line
+		} else {
+			line = "  " + line
+		}
+		lines = append(lines, line)
+	}
+	if o.err != nil {
+		lines = append(lines, lipgloss.NewStyle().Foreground(styles.CurrentTheme.Error).Render(o.err.Error()))
+	}
+	return strings.Join(lines, "\n")
+}
@@ -1 +1,169 @@
 package releases
+
+import (
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/pidanou/helm-tui/config"
+	"github.com/pidanou/helm-tui/helm"
+	"github.com/pidanou/helm-tui/helpers"
+	"github.com/pidanou/helm-tui/types"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestUpgradePreview verifies that confirming the upgrade steps shows the
+// changes of a dry run first, and only upgrades once they are confirmed.
+func TestUpgradePreview(t *testing.T) {
+	helpers.UserDir = t.TempDir()
+	client := newTestClient()
+	client.Manifests["default/web"] = "kind: Service\nmetadata:\n  name: web\n"
+	client.Previews["default/web"] = helm.Preview{Manifest: "kind: Deployment\nmetadata:\n  name: web\n"}
+	m := InitUpgradeModel(client)
+	m, _ = m.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	m.ReleaseName, m.Namespace = "web", "default"
+	m.Inputs[upgradeReleaseChartStep].SetValue("bitnami/nginx")
+	m.Inputs[upgradeReleaseValuesStep].SetValue("n")
+	m.upgradeStep = upgradeReleaseConfirmStep
+
+	enter := tea.KeyMsg{Type: tea.KeyEnter}
+	m, cmd := m.Update(enter)
+	m, _ = m.Update(cmd())
+	assert.True(t, m.previewing)
+	assert.Contains(t, m.preview.content, "Deployment/web (added)")
+	assert.Contains(t, m.preview.content, "Service/web (removed)")
+	assert.NotContains(t, client.Calls, "Upgrade web bitnami/nginx  default")
+
+	m, cmd = m.Update(enter)
+	assert.False(t, m.previewing)
+	msg := runBatch(cmd)
+	assert.Contains(t, msg, types.UpgradeMsg{})
+	assert.Contains(t, client.Calls, "Upgrade web bitnami/nginx  default")
+}
+
+// TestUpgradePreviewCancel verifies that leaving the preview goes back to the
+// confirmation without upgrading, the wizard staying open.
+func TestUpgradePreviewCancel(t *testing.T) {
+	helpers.UserDir = t.TempDir()
+	client := newTestClient()
+	client.Previews["default/web"] = helm.Preview{}
+	m := InitUpgradeModel(client)
+	m.ReleaseName, m.Namespace = "web", "default"
+	m.upgradeStep = upgradeReleaseConfirmStep
+
+	m, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m, _ = m.Update(cmd())
+	assert.True(t, m.backOnCancel(), "esc should leave the preview, not the wizard")
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	assert.False(t, m.previewing)
+	assert.Equal(t, upgradeReleaseConfirmStep, m.upgradeStep)
+	assert.NotContains(t, client.Calls, "Upgrade web   default")
+
+	m, cmd = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m, _ = m.Update(cmd())
+	assert.True(t, m.previewing, "The upgrade should be previewed again")
+}
+
+// TestUpgradeInvalidValues verifies that the upgrade does not go past values
+// which are not valid YAML.
+func TestUpgradeInvalidValues(t *testing.T) {
+	helpers.UserDir = t.TempDir()
+	m := InitUpgradeModel(newTestClient())
+	m.ReleaseName, m.Namespace = "web", "default"
+	m.Inputs[upgradeReleaseValuesStep].SetValue("y")
+	m.upgradeStep = upgradeReleaseValuesStep
+
+	m, _ = m.Update(types.EditorFinishedMsg{Invalid: &helpers.YAMLError{File: "values.yaml", Line: 2, Msg: "found character that cannot start any token"}})
+	assert.Equal(t, upgradeReleaseValuesStep, m.upgradeStep)
+	assert.Contains(t, m.View(), "Edit at line 2")
+
+	m, cmd := m.Update(types.EditorFinishedMsg{})
+	assert.Nil(t, m.invalid)
+	m, _ = m.Update(cmd())
+	assert.Equal(t, upgradeReleaseOverridesStep, m.upgradeStep)
+}
+
+// TestUpgradeOverrides verifies that the listed overrides can be edited and
+// removed before they are passed to the upgrade.
+func TestUpgradeOverrides(t *testing.T) {
+	helpers.UserDir = t.TempDir()
+	client := newTestClient()
+	m := InitUpgradeModel(client)
+	m.ReleaseName, m.Namespace = "web", "default"
+	m.Inputs[upgradeReleaseValuesStep].SetValue("n")
+	m.upgradeStep = upgradeReleaseOverridesStep
+	m.Inputs[upgradeReleaseOverridesStep].Focus()
+
+	for _, entry := range []string{"replicaCount=2", "image.tag=1.27"} {
+		m.Inputs[upgradeReleaseOverridesStep].SetValue(entry)
+		m, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	}
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyCtrlX})
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyCtrlE})
+	assert.Equal(t, "image.tag=1.27", m.Inputs[upgradeReleaseOverridesStep].Value())
+	m.Inputs[upgradeReleaseOverridesStep].SetValue("image.tag=1.28")
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	assert.Equal(t, upgradeReleaseOptionsStep, m.upgradeStep)
+	opts, err := m.upgradeOptions()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"image.tag=1.28"}, opts.Set)
+}
+
+// TestUpgradeOptions verifies that the upgrade options start from the
+// defaults of the namespace and refuse an invalid timeout.
+func TestUpgradeOptions(t *testing.T) {
+	helpers.UserDir = t.TempDir()
+	config.Current.UpgradeDefaults = map[string]config.UpgradeDefaults{
+		"default": {Atomic: true, Timeout: config.Duration{Duration: 10 * time.Minute}, Values: "reuse"},
+	}
+	t.Cleanup(func() { config.Current = config.Default() })
+	m := InitUpgradeModel(newTestClient())
+	m.ReleaseName, m.Namespace = "web", "default"
+	m.upgradeStep = upgradeReleaseOverridesStep
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	assert.Equal(t, upgradeReleaseOptionsStep, m.upgradeStep)
+	assert.Contains(t, m.View(), "[x] --atomic")
+	assert.Contains(t, m.View(), "Values: --reuse-values")
+
+	for _, msg := range []tea.KeyMsg{
+		{Type: tea.KeyDown}, {Type: tea.KeySpace, Runes: []rune(" ")}, // --wait
+		{Type: tea.KeyDown}, {Type: tea.KeyDown}, {Type: tea.KeyDown}, {Type: tea.KeyDown},
+		{Type: tea.KeySpace, Runes: []rune(" ")}, // --reset-values
+		{Type: tea.KeyDown}, {Type: tea.KeyBackspace}, {Type: tea.KeyRunes, Runes: []rune("x")},
+	} {
+		m, _ = m.Update(msg)
+	}
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	assert.Equal(t, upgradeReleaseOptionsStep, m.upgradeStep)
+	assert.Contains(t, m.View(), `--timeout must be a duration such as 5m, not "10m0x"`)
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyBackspace})
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("s")})
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	assert.Equal(t, upgradeReleaseConfirmStep, m.upgradeStep)
+	opts, err := m.upgradeOptions()
+	assert.NoError(t, err)
+	assert.True(t, opts.Atomic)
+	assert.True(t, opts.Wait)
+	assert.Equal(t, helm.ResetValues, opts.Values)
+	assert.Equal(t, 10*time.Minute, opts.Timeout)
+}
+
+// TestUpgradeRefusedOptions verifies that the upgrade is not run with
+// refused options, their error being reported instead.
+func TestUpgradeRefusedOptions(t *testing.T) {
+	client := newTestClient()
+	m := InitUpgradeModel(client)
+	m.ReleaseName, m.Namespace = "web", "default"
+	m.options = newUpgradeOptions(config.UpgradeDefaults{})
+	m.options.input(optionHistoryMax).SetValue("-1")
+
+	msg := m.upgrade().(types.UpgradeMsg)
+	assert.EqualError(t, msg.Err, `--history-max must be a number of revisions, not "-1"`)
+	assert.NotContains(t, client.Calls, "Upgrade web   default")
+}
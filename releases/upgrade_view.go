@@ -4,25 +4,73 @@ import (
 	"fmt"
 
 	"github.com/charmbracelet/lipgloss"
+	"github.com/pidanou/helm-tui/components"
 	"github.com/pidanou/helm-tui/helpers"
 	"github.com/pidanou/helm-tui/styles"
 )
 
 func (m UpgradeModel) View() string {
 	helperStyle := m.help.Styles.ShortSeparator
+	if m.previewing {
+		return m.renderPreview()
+	}
+	if m.violations != nil {
+		return components.ViolationsView(m.violationsVP, m.help, m.Inputs[upgradeReleaseChartStep].Value(), m.keys.Next, m.keys.Cancel)
+	}
 	helpView := m.help.View(m.keys) + helperStyle.Render(" • ") + m.help.View(helpers.CommonKeys)
 	if m.Inputs[upgradeReleaseChartStep].Focused() {
 		helpView = m.help.View(m.keys) + helperStyle.Render(" • ") + m.help.View(helpers.SuggestionInputKeyMap) + helperStyle.Render(" • ") + m.help.View(helpers.CommonKeys)
 	}
+	if m.Inputs[upgradeReleaseValuesFilesStep].Focused() {
+		helpView = m.help.View(m.keys) + helperStyle.Render(" • ") + m.help.View(components.ValuesFilesKeys) + helperStyle.Render(" • ") + m.help.View(helpers.SuggestionInputKeyMap)
+	}
+	if m.upgradeStep == upgradeReleaseOptionsStep {
+		helpView = m.help.View(m.keys) + helperStyle.Render(" • ") + m.help.View(upgradeOptionsKeys)
+	}
+	if m.Inputs[upgradeReleaseOverridesStep].Focused() {
+		helpView = m.help.View(m.keys) + helperStyle.Render(" • ") + m.help.View(components.OverridesKeys) + helperStyle.Render(" • ") + m.help.View(helpers.SuggestionInputKeyMap)
+	}
 	var Inputs string
 	for step := 0; step < len(m.Inputs); step++ {
+		helper := upgradeInputsHelper[step]
+		if step == upgradeReleaseOverridesStep {
+			helper = fmt.Sprintf(helper, m.overrides.Kind.Flag())
+		}
 		if step == 0 {
-			Inputs = fmt.Sprintf("%s %s", upgradeInputsHelper[step], m.Inputs[step].View())
+			Inputs = fmt.Sprintf("%s %s", helper, m.Inputs[step].View())
 			continue
 		}
-		Inputs = lipgloss.JoinVertical(lipgloss.Top, Inputs, fmt.Sprintf("%s %s", upgradeInputsHelper[step], m.Inputs[step].View()))
+		Inputs = lipgloss.JoinVertical(lipgloss.Top, Inputs, fmt.Sprintf("%s %s", helper, m.Inputs[step].View()))
+		if step == upgradeReleaseValuesFilesStep {
+			edited := m.Inputs[upgradeReleaseValuesStep].Value() == "y" || m.Inputs[upgradeReleaseValuesStep].Value() == "d"
+			if files := m.valuesFiles.View(edited); files != "" {
+				Inputs = lipgloss.JoinVertical(lipgloss.Top, Inputs, files)
+			}
+		}
+		if step == upgradeReleaseOverridesStep && len(m.overrides.Items) > 0 {
+			Inputs = lipgloss.JoinVertical(lipgloss.Top, Inputs, m.overrides.View())
+		}
+		if step == upgradeReleaseOptionsStep && m.upgradeStep >= upgradeReleaseOptionsStep {
+			Inputs = lipgloss.JoinVertical(lipgloss.Top, Inputs, m.options.View(m.upgradeStep == upgradeReleaseOptionsStep))
+		}
 	}
 	Inputs = styles.ActiveStyle.Border(styles.Border).Render(Inputs)
 	Inputs = lipgloss.JoinVertical(lipgloss.Top, Inputs)
+	if m.invalid != nil {
+		keys := keyMap{}
+		keys.Next, keys.Cancel = components.InvalidValuesKeys(m.invalid, m.keys.Next, m.keys.Cancel)
+		return lipgloss.JoinVertical(lipgloss.Top, Inputs, components.InvalidValues(m.invalid), m.help.View(keys))
+	}
 	return lipgloss.JoinVertical(lipgloss.Top, Inputs, helpView)
 }
+
+// renderPreview shows the changes of the upgrade to confirm.
+func (m UpgradeModel) renderPreview() string {
+	title := fmt.Sprintf(" Changes of upgrading %s to %s ", m.ReleaseName, m.Inputs[upgradeReleaseChartStep].Value())
+	topBorder := styles.GenerateTopBorderWithTitle(title, m.previewVP.Width, styles.Border, styles.ActiveStyle.Foreground(styles.CurrentTheme.Highlight))
+	baseStyle := styles.ActiveStyle.Border(styles.Border, false, true, true)
+	keys := keyMap{Confirm: diffKeys.Next, Next: m.keys.Next, Previous: diffKeys.Previous, Cancel: m.keys.Cancel}
+	keys.Next.SetHelp(m.keys.Next.Help().Key, "Upgrade")
+	keys.Next.SetEnabled(m.preview.err == nil)
+	return lipgloss.JoinVertical(lipgloss.Top, topBorder, baseStyle.Render(m.previewVP.View()), m.help.View(keys))
+}
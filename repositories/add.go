@@ -2,8 +2,10 @@ package repositories
 
 import (
 	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/pidanou/helm-tui/helm"
 )
 
 const (
@@ -17,6 +19,7 @@ var addInputsHelper = []string{
 }
 
 type AddModel struct {
+	client  helm.HelmClient
 	addStep int
 	Inputs  []textinput.Model
 	width   int
@@ -25,11 +28,11 @@ type AddModel struct {
 	keys    keyMap
 }
 
-func InitAddModel() AddModel {
+func InitAddModel(client helm.HelmClient) AddModel {
 	repoName := textinput.New()
 	url := textinput.New()
 	inputs := []textinput.Model{repoName, url}
-	m := AddModel{addStep: repoNameStep, Inputs: inputs, help: help.New(), keys: addKeys}
+	m := AddModel{client: client, addStep: repoNameStep, Inputs: inputs, help: help.New(), keys: addKeys}
 	return m
 }
 
@@ -48,8 +51,8 @@ func (m AddModel) Update(msg tea.Msg) (AddModel, tea.Cmd) {
 		m.Inputs[repoNameStep].Width = msg.Width - 5 - len(inputsHelper[0])
 		m.Inputs[urlStep].Width = msg.Width - 5 - len(inputsHelper[1])
 	case tea.KeyMsg:
-		switch msg.String() {
-		case "enter":
+		switch {
+		case key.Matches(msg, m.keys.Next):
 			if m.addStep == urlStep {
 				cmds = append(cmds, m.addRepo(m.Inputs[repoNameStep].Value(), m.Inputs[urlStep].Value()))
 				cmd = m.resetAllInputs()
@@ -72,7 +75,7 @@ func (m AddModel) Update(msg tea.Msg) (AddModel, tea.Cmd) {
 			}
 
 			return m, tea.Batch(cmds...)
-		case "esc":
+		case key.Matches(msg, m.keys.Cancel):
 			m.addStep = 0
 			for i := 0; i <= len(m.Inputs)-1; i++ {
 				m.Inputs[i].Blur()
@@ -1,9 +1,6 @@
 package repositories
 
 import (
-	"bytes"
-	"os/exec"
-
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/pidanou/helm-tui/types"
 )
@@ -35,16 +32,7 @@ func (m AddModel) resetAllInputs() tea.Cmd {
 
 func (m AddModel) addRepo(repoName, url string) tea.Cmd {
 	return func() tea.Msg {
-		var stdout, stderr bytes.Buffer
-
-		var cmd *exec.Cmd
-		cmd = exec.Command("helm", "repo", "add", repoName, url)
-		// Create the command
-		cmd.Stdout = &stdout
-		cmd.Stderr = &stderr
-
-		// Run the command
-		err := cmd.Run()
+		err := m.client.RepoAdd(repoName, url)
 		if err != nil {
 			return types.AddRepoMsg{Err: err}
 		}
@@ -1,7 +1,15 @@
 package repositories
 
-import "github.com/charmbracelet/bubbles/key"
+import (
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/pidanou/helm-tui/helpers"
+)
 
 var addKeys = keyMap{
+	Next:   key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "Next")),
 	Cancel: key.NewBinding(key.WithKeys("esc"), key.WithHelp("esc", "Cancel")),
 }
+
+func init() {
+	helpers.RegisterKeys("repositories.add", map[string]*key.Binding{"next": &addKeys.Next, "cancel": &addKeys.Cancel}, "global")
+}
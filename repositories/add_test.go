@@ -4,12 +4,13 @@ import (
 	"testing"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/pidanou/helm-tui/helm"
 	"github.com/stretchr/testify/assert"
 )
 
 // TestInitAddModel verifies that the AddModel initializes correctly.
 func TestInitAddModel(t *testing.T) {
-	model := InitAddModel()
+	model := InitAddModel(helm.NewFakeClient())
 
 	assert.Equal(t, repoNameStep, model.addStep, "Initial installStep should be repoNameStep")
 	assert.Equal(t, 2, len(model.Inputs), "AddModel should have 2 inputs")
@@ -17,7 +18,7 @@ func TestInitAddModel(t *testing.T) {
 
 // TestAddModelEnterKey verifies that pressing Enter advances the install step.
 func TestAddModelEnterKey(t *testing.T) {
-	model := InitAddModel()
+	model := InitAddModel(helm.NewFakeClient())
 	msg := tea.KeyMsg{Type: tea.KeyEnter}
 
 	updatedModel, _ := model.Update(msg)
@@ -28,7 +29,7 @@ func TestAddModelEnterKey(t *testing.T) {
 
 // TestAddModelEscKey verifies that pressing Esc resets the install step and clears inputs.
 func TestAddModelEscKey(t *testing.T) {
-	model := InitAddModel()
+	model := InitAddModel(helm.NewFakeClient())
 	model.Inputs[repoNameStep].SetValue("test-repo")
 	model.Inputs[urlStep].SetValue("http://example.com")
 
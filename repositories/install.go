@@ -1,11 +1,14 @@
 package repositories
 
 import (
-	"fmt"
-
+	"errors"
 	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/pidanou/helm-tui/components"
+	"github.com/pidanou/helm-tui/helm"
 	"github.com/pidanou/helm-tui/helpers"
 	"github.com/pidanou/helm-tui/types"
 )
@@ -13,18 +16,23 @@ import (
 const (
 	nameStep installStep = iota
 	namespaceStep
+	valuesFilesStep
 	valuesStep
+	overridesStep
 	confirmStep
 )
 
 var inputsHelper = []string{
 	"Enter release name",
 	"Enter namespace (empty for default)",
+	"Add values file (empty to continue)",
 	"Edit default values ? y/n",
-	"Enter to install",
+	"Add %s key=value (empty to continue)",
+	"Enter to install, p to preview",
 }
 
 type InstallModel struct {
+	client      helm.HelmClient
 	installStep installStep
 	Chart       string
 	Version     string
@@ -33,15 +41,37 @@ type InstallModel struct {
 	height      int
 	help        help.Model
 	keys        keyMap
+	// previewing shows the manifests rendered by a dry run of the install
+	// until it is confirmed or the values are edited again.
+	previewing bool
+	preview    installPreviewMsg
+	previewVP  viewport.Model
+	// invalid is why the values just edited were refused, until they are
+	// edited again or the wizard is aborted.
+	invalid *helpers.YAMLError
+	// violations are the edited values breaking the schema of the chart,
+	// shown until they are edited again or esc goes back to the values step.
+	violations   []helm.Violation
+	violationsVP viewport.Model
+	// valuesFiles are local values files passed to helm, in order, before
+	// the edited values.
+	valuesFiles components.ValuesFiles
+	// overrides are the key=value overrides of the values, passed with
+	// --set, --set-string or --set-json.
+	overrides components.Overrides
 }
 
-func InitInstallModel(chart, version string) InstallModel {
+func InitInstallModel(client helm.HelmClient, chart, version string) InstallModel {
 	name := textinput.New()
 	namespace := textinput.New()
+	valuesFiles := textinput.New()
 	value := textinput.New()
+	overrides := textinput.New()
 	confirm := textinput.New()
-	inputs := []textinput.Model{name, namespace, value, confirm}
-	m := InstallModel{installStep: nameStep, Inputs: inputs, help: help.New(), Chart: chart, Version: version, keys: installKeys}
+	inputs := []textinput.Model{name, namespace, valuesFiles, value, overrides, confirm}
+	m := InstallModel{client: client, installStep: nameStep, Inputs: inputs, help: help.New(), Chart: chart, Version: version, keys: installKeys, valuesFiles: components.NewValuesFiles(), overrides: components.NewOverrides()}
+	m.Inputs[valuesFilesStep].ShowSuggestions = true
+	m.Inputs[overridesStep].ShowSuggestions = true
 	return m
 }
 
@@ -59,32 +89,110 @@ func (m InstallModel) Update(msg tea.Msg) (InstallModel, tea.Cmd) {
 		m.help.Width = msg.Width
 		m.Inputs[nameStep].Width = msg.Width - 5 - len(inputsHelper[0])
 		m.Inputs[namespaceStep].Width = msg.Width - 5 - len(inputsHelper[1])
-		m.Inputs[valuesStep].Width = msg.Width - 5 - len(inputsHelper[2])
-		m.Inputs[confirmStep].Width = msg.Width - 5 - len(inputsHelper[3])
+		m.Inputs[valuesFilesStep].Width = msg.Width - 5 - len(inputsHelper[valuesFilesStep])
+		m.Inputs[valuesStep].Width = msg.Width - 5 - len(inputsHelper[valuesStep])
+		m.Inputs[overridesStep].Width = msg.Width - 5 - len(inputsHelper[overridesStep])
+		m.Inputs[confirmStep].Width = msg.Width - 5 - len(inputsHelper[confirmStep])
+	case installPreviewMsg:
+		m.previewing = true
+		m.preview = msg
+		m.previewVP = viewport.New(m.width-2, m.height-3) // borders and help
+		content := msg.content
+		if msg.err != nil {
+			content = msg.err.Error()
+		}
+		m.previewVP.SetContent(content)
+		return m, nil
 	case types.EditorFinishedMsg:
-		m.installStep++
-		for i := 0; i <= len(m.Inputs)-1; i++ {
-			if i == int(m.installStep) {
-				cmds[i] = m.Inputs[i].Focus()
-				continue
-			}
-			m.Inputs[i].Blur()
+		if errors.As(msg.Invalid, &m.invalid) {
+			return m, nil
 		}
-		return m, tea.Batch(cmds...)
+		m.invalid = nil
+		if msg.Err == nil {
+			return m, components.CheckValues(m.client, m.Chart, m.Version, m.installOptions("y").Sources())
+		}
+		return m, m.nextStep()
+	case components.SchemaMsg:
+		if msg.Err != nil {
+			helpers.Println("checking values against the chart schema:", msg.Err)
+		}
+		if len(msg.Violations) > 0 {
+			m.violations = msg.Violations
+			m.violationsVP = components.ViolationsViewport(m.width, m.height, msg.Violations)
+			return m, nil
+		}
+		return m, m.nextStep()
+	case components.ValuePathsMsg:
+		m.Inputs[overridesStep].SetSuggestions(msg.Paths)
+		return m, nil
 	case types.InstallMsg:
+		m.previewing = false
 		m.installStep = 0
-		releaseName := m.Inputs[nameStep].Value()
-		namespace := m.Inputs[namespaceStep].Value()
-		if namespace == "" {
-			namespace = "default"
-		}
-		folder := fmt.Sprintf("%s/%s/%s", helpers.UserDir, namespace, releaseName)
-		cmds = append(cmds, m.cleanValueFile(folder), m.blurAllInputs(), m.resetAllInputs(), m.Inputs[nameStep].Focus())
+		m.valuesFiles.Reset()
+		m.overrides.Reset()
+		cmds = append(cmds, m.cleanValueFile(m.valuesFolder()), m.blurAllInputs(), m.resetAllInputs(), m.Inputs[nameStep].Focus())
 
 		return m, tea.Batch(cmds...)
 	case tea.KeyMsg:
-		switch msg.String() {
-		case "enter":
+		if m.invalid != nil {
+			switch {
+			case key.Matches(msg, m.keys.Next):
+				cmd = helpers.OpenFileAt(m.invalid.File, m.invalid.Line, 0)
+				m.invalid = nil
+				return m, cmd
+			case key.Matches(msg, m.keys.Cancel):
+				m.invalid = nil
+			default:
+				return m, nil
+			}
+		}
+		if m.violations != nil {
+			switch {
+			case key.Matches(msg, m.keys.Next):
+				line := components.FirstViolationLine(m.violations)
+				m.violations = nil
+				return m, helpers.OpenFileAt(m.installOptions("y").ValuesFile, line, 0)
+			case key.Matches(msg, m.keys.Cancel):
+				m.violations = nil
+			default:
+				m.violationsVP, cmd = m.violationsVP.Update(msg)
+			}
+			return m, cmd
+		}
+		if m.previewing {
+			switch {
+			case key.Matches(msg, m.keys.Next):
+				if m.preview.err == nil {
+					m.previewing = false
+					m.installStep = 0
+					return m, m.installPackage(m.Inputs[valuesStep].Value())
+				}
+			case key.Matches(msg, components.ManifestKeys.Next):
+				components.JumpTo(&m.previewVP, m.preview.sections, true)
+			case key.Matches(msg, components.ManifestKeys.Previous):
+				components.JumpTo(&m.previewVP, m.preview.sections, false)
+			case key.Matches(msg, m.keys.Cancel):
+				// back to the values, kept for the editor to open again
+				m.previewing = false
+				m.installStep = valuesStep
+				m.Inputs[confirmStep].Blur()
+				return m, m.Inputs[valuesStep].Focus()
+			default:
+				m.previewVP, cmd = m.previewVP.Update(msg)
+				return m, cmd
+			}
+			return m, nil
+		}
+		if m.installStep == valuesFilesStep && m.valuesFiles.Update(&m.Inputs[valuesFilesStep], msg) {
+			return m, nil
+		}
+		if m.installStep == overridesStep && m.overrides.Update(&m.Inputs[overridesStep], msg) {
+			return m, nil
+		}
+		switch {
+		case key.Matches(msg, m.keys.Preview) && m.installStep == confirmStep:
+			return m, m.previewInstall
+		case key.Matches(msg, m.keys.Next):
 			if m.installStep == confirmStep {
 				m.installStep = 0
 
@@ -107,19 +215,25 @@ func (m InstallModel) Update(msg tea.Msg) (InstallModel, tea.Cmd) {
 				}
 			}
 
-			m.installStep++
-
-			for i := 0; i <= len(m.Inputs)-1; i++ {
-				if i == int(m.installStep) {
-					cmds[i] = m.Inputs[i].Focus()
-					continue
+			if m.installStep == valuesFilesStep && m.Inputs[valuesFilesStep].Value() != "" {
+				if m.valuesFiles.Add(m.Inputs[valuesFilesStep].Value()) == nil {
+					m.Inputs[valuesFilesStep].SetValue("")
 				}
-				m.Inputs[i].Blur()
+				return m, nil
+			}
+			if m.installStep == overridesStep && m.Inputs[overridesStep].Value() != "" {
+				if m.overrides.Add(m.Inputs[overridesStep].Value()) {
+					m.Inputs[overridesStep].SetValue("")
+				}
+				return m, nil
 			}
 
-			return m, tea.Batch(cmds...)
-		case "esc":
+			return m, m.nextStep()
+		case key.Matches(msg, m.keys.Cancel):
+			cmds = append(cmds, m.cleanValueFile(m.valuesFolder()))
 			m.installStep = 0
+			m.valuesFiles.Reset()
+			m.overrides.Reset()
 			for i := 0; i <= len(m.Inputs)-1; i++ {
 				m.Inputs[i].Blur()
 				m.Inputs[i].SetValue("")
@@ -128,5 +242,31 @@ func (m InstallModel) Update(msg tea.Msg) (InstallModel, tea.Cmd) {
 		}
 	}
 	cmds = append(cmds, m.updateInputs(msg))
+	if m.Inputs[valuesFilesStep].Focused() {
+		m.Inputs[valuesFilesStep].SetSuggestions(components.PathSuggestions(m.Inputs[valuesFilesStep].Value()))
+	}
 	return m, tea.Batch(cmds...)
 }
+
+// nextStep focuses the input of the step after the current one.
+func (m *InstallModel) nextStep() tea.Cmd {
+	m.installStep++
+	cmds := make([]tea.Cmd, len(m.Inputs))
+	for i := range m.Inputs {
+		if i == int(m.installStep) {
+			cmds[i] = m.Inputs[i].Focus()
+			continue
+		}
+		m.Inputs[i].Blur()
+	}
+	if m.installStep == overridesStep {
+		cmds = append(cmds, components.FetchValuePaths(m.client, m.Chart, m.Version))
+	}
+	return tea.Batch(cmds...)
+}
+
+// backOnCancel reports whether esc goes back to a step of the wizard
+// instead of closing it.
+func (m InstallModel) backOnCancel() bool {
+	return m.previewing || m.violations != nil
+}
@@ -1,12 +1,13 @@
 package repositories
 
 import (
-	"bytes"
 	"fmt"
 	"os"
-	"os/exec"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/pidanou/helm-tui/components"
+	"github.com/pidanou/helm-tui/config"
+	"github.com/pidanou/helm-tui/helm"
 	"github.com/pidanou/helm-tui/helpers"
 	"github.com/pidanou/helm-tui/types"
 )
@@ -36,29 +37,52 @@ func (m InstallModel) resetAllInputs() tea.Cmd {
 	return nil
 }
 
-func (m InstallModel) installPackage(mode string) tea.Cmd {
-	releaseName := m.Inputs[nameStep].Value()
+func (m InstallModel) namespace() string {
 	namespace := m.Inputs[namespaceStep].Value()
 	if namespace == "" {
-		namespace = "default"
+		namespace = config.Current.Namespace()
 	}
-	folder := fmt.Sprintf("%s/%s/%s", helpers.UserDir, namespace, releaseName)
-	file := fmt.Sprintf("%s/values.yaml", folder)
-	return func() tea.Msg {
-		var stdout, stderr bytes.Buffer
-
-		var cmd *exec.Cmd
-		// Create the command
-		if mode == "y" {
-			cmd = exec.Command("helm", "install", releaseName, m.Chart, "--version", m.Version, "--values", file, "--namespace", namespace, "--create-namespace")
-		} else {
-			cmd = exec.Command("helm", "install", releaseName, m.Chart, "--version", m.Version, "--namespace", namespace, "--create-namespace")
-		}
-		cmd.Stdout = &stdout
-		cmd.Stderr = &stderr
+	return namespace
+}
+
+// valuesFolder holds the values edited for the release being installed.
+func (m InstallModel) valuesFolder() string {
+	return fmt.Sprintf("%s/%s/%s", helpers.UserDir, m.namespace(), m.Inputs[nameStep].Value())
+}
+
+func (m InstallModel) installOptions(mode string) helm.InstallOptions {
+	opts := helm.InstallOptions{ReleaseName: m.Inputs[nameStep].Value(), Chart: m.Chart, Version: m.Version, Namespace: m.namespace(), ValuesFiles: m.valuesFiles.Paths}
+	opts.Set, opts.SetString, opts.SetJSON = m.overrides.Flags()
+	if mode == "y" {
+		opts.ValuesFile = fmt.Sprintf("%s/values.yaml", m.valuesFolder())
+	}
+	return opts
+}
+
+// installPreviewMsg holds the manifests and notes rendered by a dry run of
+// an install.
+type installPreviewMsg struct {
+	content string
+	// sections are the lines of content the resource kinds and the notes
+	// start at.
+	sections []int
+	err      error
+}
+
+// previewInstall renders the install with a dry run.
+func (m InstallModel) previewInstall() tea.Msg {
+	preview, err := m.client.PreviewInstall(m.installOptions(m.Inputs[valuesStep].Value()))
+	if err != nil {
+		return installPreviewMsg{err: err}
+	}
+	content, sections := components.RenderManifest(preview.Manifest, preview.Notes)
+	return installPreviewMsg{content: content, sections: sections}
+}
 
-		// Run the command
-		err := cmd.Run()
+func (m InstallModel) installPackage(mode string) tea.Cmd {
+	opts := m.installOptions(mode)
+	return func() tea.Msg {
+		err := m.client.Install(opts)
 		if err != nil {
 			return types.InstallMsg{Err: err}
 		}
@@ -67,40 +91,22 @@ func (m InstallModel) installPackage(mode string) tea.Cmd {
 	}
 }
 
+// openEditorDefaultValues edits the default values of the chart, commented
+// out under values files, or the values already edited when coming back from
+// the preview.
 func (m InstallModel) openEditorDefaultValues() tea.Cmd {
-	var stdout, stderr bytes.Buffer
-	releaseName := m.Inputs[nameStep].Value()
-	namespace := m.Inputs[namespaceStep].Value()
-	if namespace == "" {
-		namespace = "default"
-	}
-	folder := fmt.Sprintf("%s/%s/%s", helpers.UserDir, namespace, releaseName)
+	folder := m.valuesFolder()
 	_ = os.MkdirAll(folder, 0755)
 	file := fmt.Sprintf("%s/values.yaml", folder)
-	packageName := m.Chart
-	version := m.Version
+	if _, err := os.Stat(file); err == nil {
+		return helpers.OpenFile(file)
+	}
 
-	cmd := exec.Command("helm", "show", "values", packageName, "--version", version)
-	cmd.Stdout = &stdout
-	cmd.Stderr = &stderr
-	err := cmd.Run()
+	values, err := m.client.ShowValues(m.Chart, m.Version)
 	if err != nil {
 		return func() tea.Msg { return types.EditorFinishedMsg{Err: err} }
 	}
-	err = os.WriteFile(file, stdout.Bytes(), 0644)
-	if err != nil {
-		return func() tea.Msg {
-			return types.EditorFinishedMsg{Err: err}
-		}
-	}
-	editor := os.Getenv("EDITOR")
-	if editor == "" {
-		editor = "vim"
-	}
-	c := exec.Command(editor, file)
-	return tea.ExecProcess(c, func(err error) tea.Msg {
-		return types.EditorFinishedMsg{Err: err}
-	})
+	return helpers.WriteAndOpenFile([]byte(m.valuesFiles.Seed(values)), file)
 }
 
 func (m InstallModel) cleanValueFile(folder string) tea.Cmd {
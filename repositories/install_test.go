@@ -1 +1,62 @@
 package repositories
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/pidanou/helm-tui/helm"
+	"github.com/pidanou/helm-tui/helpers"
+	"github.com/pidanou/helm-tui/types"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestInstallPreview verifies that p at the confirm step renders the install
+// of the selected version with a dry run before installing it.
+func TestInstallPreview(t *testing.T) {
+	helpers.UserDir = t.TempDir()
+	client := helm.NewFakeClient()
+	client.Previews["default/web"] = helm.Preview{Manifest: "kind: Deployment\nmetadata:\n  name: web\n"}
+	m := InitInstallModel(client, "bitnami/nginx", "1.0.0")
+	m, _ = m.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	m.Inputs[nameStep].SetValue("web")
+	m.Inputs[namespaceStep].SetValue("default")
+	m.Inputs[valuesStep].SetValue("n")
+	m.installStep = confirmStep
+
+	m, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("p")})
+	m, _ = m.Update(cmd())
+	assert.True(t, m.previewing)
+	assert.Contains(t, m.preview.content, "━━ Deployment (1)")
+	assert.Contains(t, client.Calls, "PreviewInstall web bitnami/nginx 1.0.0 default")
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	assert.False(t, m.previewing)
+	assert.Equal(t, valuesStep, m.installStep)
+
+	m.installStep = confirmStep
+	m, cmd = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("p")})
+	m, _ = m.Update(cmd())
+	m, cmd = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	assert.Equal(t, types.InstallMsg{}, cmd())
+	assert.Contains(t, client.Calls, "Install web bitnami/nginx 1.0.0 default")
+}
+
+// TestInstallValuesFilesCompletion verifies that the paths of values files
+// are completed as they are typed.
+func TestInstallValuesFilesCompletion(t *testing.T) {
+	dir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "prod.yaml"), nil, 0644))
+	m := InitInstallModel(helm.NewFakeClient(), "bitnami/nginx", "1.0.0")
+	m.installStep = valuesFilesStep
+	m.Inputs[valuesFilesStep].Focus()
+	m.Inputs[valuesFilesStep].SetValue(dir + "/pr")
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("o")})
+	assert.Equal(t, []string{dir + "/prod.yaml"}, m.Inputs[valuesFilesStep].AvailableSuggestions())
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyTab})
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	assert.Equal(t, []string{dir + "/prod.yaml"}, m.installOptions("n").ValuesFiles)
+}
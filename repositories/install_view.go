@@ -4,22 +4,68 @@ import (
 	"fmt"
 
 	"github.com/charmbracelet/lipgloss"
+	"github.com/pidanou/helm-tui/components"
 	"github.com/pidanou/helm-tui/helpers"
 	"github.com/pidanou/helm-tui/styles"
 )
 
 func (m InstallModel) View() string {
+	if m.previewing {
+		return m.renderPreview()
+	}
+	if m.violations != nil {
+		return components.ViolationsView(m.violationsVP, m.help, m.Chart+" "+m.Version, m.keys.Next, m.keys.Cancel)
+	}
 	helperStyle := m.help.Styles.ShortSeparator
-	helpView := m.help.View(m.keys) + helperStyle.Render(" • ") + m.help.View(helpers.CommonKeys)
+	keys := m.keys
+	keys.Preview.SetEnabled(m.installStep == confirmStep)
+	helpView := m.help.View(keys) + helperStyle.Render(" • ") + m.help.View(helpers.CommonKeys)
+	if m.Inputs[valuesFilesStep].Focused() {
+		helpView = m.help.View(keys) + helperStyle.Render(" • ") + m.help.View(components.ValuesFilesKeys) + helperStyle.Render(" • ") + m.help.View(helpers.SuggestionInputKeyMap)
+	}
+	if m.Inputs[overridesStep].Focused() {
+		helpView = m.help.View(keys) + helperStyle.Render(" • ") + m.help.View(components.OverridesKeys) + helperStyle.Render(" • ") + m.help.View(helpers.SuggestionInputKeyMap)
+	}
 	var inputs string
 	for step := 0; step < len(m.Inputs); step++ {
+		helper := inputsHelper[step]
+		if step == int(overridesStep) {
+			helper = fmt.Sprintf(helper, m.overrides.Kind.Flag())
+		}
 		if step == 0 {
-			inputs = fmt.Sprintf("%s %s", inputsHelper[step], m.Inputs[step].View())
+			inputs = fmt.Sprintf("%s %s", helper, m.Inputs[step].View())
 			continue
 		}
-		inputs = lipgloss.JoinVertical(lipgloss.Top, inputs, fmt.Sprintf("%s %s", inputsHelper[step], m.Inputs[step].View()))
+		inputs = lipgloss.JoinVertical(lipgloss.Top, inputs, fmt.Sprintf("%s %s", helper, m.Inputs[step].View()))
+		if step == int(valuesFilesStep) {
+			if files := m.valuesFiles.View(m.Inputs[valuesStep].Value() == "y"); files != "" {
+				inputs = lipgloss.JoinVertical(lipgloss.Top, inputs, files)
+			}
+		}
+		if step == int(overridesStep) && len(m.overrides.Items) > 0 {
+			inputs = lipgloss.JoinVertical(lipgloss.Top, inputs, m.overrides.View())
+		}
 	}
 	inputs = styles.ActiveStyle.Border(styles.Border).Render(inputs)
 	inputs = lipgloss.JoinVertical(lipgloss.Top, inputs)
+	if m.invalid != nil {
+		keys := keyMap{}
+		keys.Next, keys.Cancel = components.InvalidValuesKeys(m.invalid, m.keys.Next, m.keys.Cancel)
+		helpView = m.help.View(keys)
+		return lipgloss.JoinVertical(lipgloss.Top, "\n", "Installing "+m.Chart+" "+m.Version, "\n", inputs, components.InvalidValues(m.invalid), helpView)
+	}
 	return lipgloss.JoinVertical(lipgloss.Top, "\n", "Installing "+m.Chart+" "+m.Version, "\n", inputs, helpView)
 }
+
+// renderPreview shows the manifests and notes of the install to confirm.
+func (m InstallModel) renderPreview() string {
+	title := fmt.Sprintf(" Preview of installing %s %s as %s in %s ", m.Chart, m.Version, m.Inputs[nameStep].Value(), m.namespace())
+	topBorder := styles.GenerateTopBorderWithTitle(title, m.previewVP.Width, styles.Border, styles.ActiveStyle.Foreground(styles.CurrentTheme.Highlight))
+	baseStyle := styles.ActiveStyle.Border(styles.Border, false, true, true)
+	keys := keyMap{Next: m.keys.Next, Cancel: m.keys.Cancel}
+	keys.Next.SetHelp(m.keys.Next.Help().Key, "Install")
+	keys.Next.SetEnabled(m.preview.err == nil)
+	keys.Cancel.SetHelp(m.keys.Cancel.Help().Key, "Edit values")
+	helpView := m.help.View(components.ManifestKeys) + m.help.Styles.ShortSeparator.Render(" • ") + m.help.View(keys)
+	return lipgloss.JoinVertical(lipgloss.Top, topBorder, baseStyle.Render(m.previewVP.View()), helpView)
+}
@@ -2,10 +2,14 @@ package repositories
 
 import (
 	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/table"
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/pidanou/helm-tui/components"
+	"github.com/pidanou/helm-tui/config"
+	"github.com/pidanou/helm-tui/helm"
+	"github.com/pidanou/helm-tui/helpers"
 	"github.com/pidanou/helm-tui/types"
 )
 
@@ -19,6 +23,7 @@ const (
 )
 
 type Model struct {
+	client           helm.HelmClient
 	selectedView     selectedView
 	keys             []keyMap
 	tables           []table.Model
@@ -31,6 +36,11 @@ type Model struct {
 	showDefaultValue bool
 	width            int
 	height           int
+	// templating shows the manifest rendered from the templates of the
+	// selected version.
+	templating bool
+	template   templateMsg
+	templateVP viewport.Model
 }
 
 var repositoryCols = []components.ColumnDefinition{
@@ -48,7 +58,11 @@ var versionsCols = []components.ColumnDefinition{
 	{Title: "Description", FlexFactor: 1},
 }
 
-func InitModel() (tea.Model, tea.Cmd) {
+// tableNames and tableCols are indexed by selectedView.
+var tableNames = []string{"repositories", "packages", "versions"}
+var tableCols = [][]components.ColumnDefinition{repositoryCols, packagesCols, versionsCols}
+
+func InitModel(client helm.HelmClient) (tea.Model, tea.Cmd) {
 	tables := []table.Model{}
 	t := components.GenerateTable()
 	repoTable := t
@@ -59,11 +73,12 @@ func InitModel() (tea.Model, tea.Cmd) {
 	repoTable.Focus()
 	keys := generateKeys()
 	m := Model{
+		client:           client,
 		tables:           tables,
 		selectedView:     listView,
 		keys:             keys,
-		installModel:     InitInstallModel("", ""),
-		addModel:         InitAddModel(),
+		installModel:     InitInstallModel(client, "", ""),
+		addModel:         InitAddModel(client),
 		help:             help.New(),
 		installing:       false,
 		adding:           false,
@@ -73,6 +88,17 @@ func InitModel() (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+// readOnly reports whether changes are refused in the current kube context.
+func (m Model) readOnly() bool {
+	return config.Current.ReadOnlyIn(m.client.KubeContext())
+}
+
+// InputFocused reports whether the install or add wizard, or the rendered
+// templates, are capturing key presses.
+func (m Model) InputFocused() bool {
+	return m.installing || m.adding || m.templating
+}
+
 func (m Model) Init() tea.Cmd {
 	return m.list
 }
@@ -83,7 +109,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	if m.installing {
 		switch msg := msg.(type) {
 		case tea.KeyMsg:
-			if msg.String() == "esc" {
+			if key.Matches(msg, installKeys.Cancel) && !m.installModel.backOnCancel() {
 				m.installing = false
 				m.installModel, cmd = m.installModel.Update(msg)
 				cmds = append(cmds, cmd)
@@ -100,7 +126,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	if m.adding {
 		switch msg := msg.(type) {
 		case tea.KeyMsg:
-			if msg.String() == "esc" {
+			if key.Matches(msg, addKeys.Cancel) {
 				m.adding = false
 				m.addModel, cmd = m.addModel.Update(msg)
 				cmds = append(cmds, cmd)
@@ -122,20 +148,27 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		components.SetTable(&m.tables[listView], repositoryCols, m.width/4)
 		components.SetTable(&m.tables[packagesView], packagesCols, m.width/4)
 		components.SetTable(&m.tables[versionsView], versionsCols, 2*m.width/4)
+		for view := range m.tables {
+			m.sortTable(selectedView(view))
+		}
 		m.defaultValueVP.Width = m.width - 2
-		m.installModel.Update(msg)
-		m.addModel.Update(msg)
+		m.templateVP.Width, m.templateVP.Height = m.width-2, m.height-3
+		m.installModel, _ = m.installModel.Update(msg)
+		m.addModel, _ = m.addModel.Update(msg)
 		m.help.Width = msg.Width
 	case types.ListRepoMsg:
 		m.tables[listView].SetRows(msg.Content)
+		m.sortTable(listView)
 		m.tables[listView], cmd = m.tables[listView].Update(msg)
 		cmds = append(cmds, cmd, m.searchPackages)
 	case types.PackagesMsg:
 		m.tables[packagesView].SetRows(msg.Content)
+		m.sortTable(packagesView)
 		m.tables[packagesView], cmd = m.tables[packagesView].Update(msg)
 		cmds = append(cmds, cmd, m.searchPackageVersions)
 	case types.PackageVersionsMsg:
 		m.tables[versionsView].SetRows(msg.Content)
+		m.sortTable(versionsView)
 		m.tables[versionsView], cmd = m.tables[versionsView].Update(msg)
 		cmds = append(cmds, cmd)
 	case types.RemoveMsg:
@@ -151,11 +184,33 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		cmds = append(cmds, m.list)
 	case types.DefaultValueMsg:
 		m.defaultValueVP.SetContent(msg.Content)
+	case templateMsg:
+		m.showTemplate(msg)
+		return m, nil
+	case types.EditorFinishedMsg:
+		if m.templating && msg.Invalid != nil {
+			m.showTemplate(templateMsg{chart: m.template.chart, version: m.template.version, edited: true, err: msg.Invalid})
+			return m, nil
+		}
+		if m.templating && msg.Err == nil {
+			return m, m.renderTemplate(m.template.chart, m.template.version, true)
+		}
 
 	// handle key presses
 	case tea.KeyMsg:
-		switch msg.String() {
-		case "i":
+		if m.templating {
+			return m.updateTemplate(msg)
+		}
+		keys := m.keys[m.selectedView]
+		if m.readOnly() && key.Matches(msg, keys.Install, keys.Add, keys.Delete) {
+			return m, helpers.RefuseReadOnly
+		}
+		switch {
+		case key.Matches(msg, components.SortKeys.Column, components.SortKeys.Order):
+			components.SortKey(msg, tableNames[m.selectedView], tableCols[m.selectedView])
+			m.sortTable(m.selectedView)
+			return m, nil
+		case key.Matches(msg, keys.Install):
 			if m.tables[packagesView].SelectedRow() != nil && m.tables[versionsView].SelectedRow() != nil {
 				m.installModel.Chart = m.tables[packagesView].SelectedRow()[0]
 				m.installModel.Version = m.tables[versionsView].SelectedRow()[0]
@@ -163,41 +218,45 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				cmd = m.installModel.Init()
 				return m, cmd
 			}
-		case "a":
+		case key.Matches(msg, keys.Add):
 			m.adding = true
 			cmd = m.addModel.Init()
 			return m, cmd
-		case "v":
+		case key.Matches(msg, keys.ShowValues):
 			m.showDefaultValue = true
 			return m, m.getDefaultValue
-		case "down", "up", "j", "k":
+		case key.Matches(msg, keys.Template):
+			if m.tables[packagesView].SelectedRow() != nil && m.tables[versionsView].SelectedRow() != nil {
+				return m, m.renderTemplate(m.tables[packagesView].SelectedRow()[0], m.tables[versionsView].SelectedRow()[0], false)
+			}
+		case key.Matches(msg, components.TableKeys.LineUp, components.TableKeys.LineDown):
 			switch m.selectedView {
 			case listView:
 				cmds = append(cmds, m.searchPackages)
 			case packagesView:
 				cmds = append(cmds, m.searchPackageVersions)
 			}
-		case "l", "right":
+		case key.Matches(msg, keys.Right, keys.Select):
 			switch m.selectedView {
 			case versionsView:
 			default:
 				m.selectedView++
 			}
 			m.FocusOnlyTable(m.selectedView)
-		case "D":
+		case key.Matches(msg, keys.Delete):
 			cmds = append(cmds, m.remove)
-		case "h", "left":
+		case key.Matches(msg, keys.Left):
 			switch m.selectedView {
 			case listView:
 			default:
 				m.selectedView--
 			}
 			m.FocusOnlyTable(m.selectedView)
-		case "u":
+		case key.Matches(msg, keys.Update):
 			return m, m.update
-		case "r":
+		case key.Matches(msg, keys.Refresh):
 			return m, m.list
-		case "esc":
+		case key.Matches(msg, defaultValuesKeyHelp.Cancel):
 			m.installing = false
 			m.adding = false
 			m.showDefaultValue = false
@@ -215,6 +274,11 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, tea.Batch(cmds...)
 }
 
+// sortTable sorts the table of view in its remembered order.
+func (m *Model) sortTable(view selectedView) {
+	components.Sort(&m.tables[view], tableNames[view], tableCols[view])
+}
+
 func (m *Model) FocusOnlyTable(index selectedView) {
 	m.tables[listView].Blur()
 	m.tables[packagesView].Blur()
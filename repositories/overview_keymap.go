@@ -2,24 +2,32 @@ package repositories
 
 import (
 	"github.com/charmbracelet/bubbles/key"
+	"github.com/pidanou/helm-tui/helpers"
 )
 
 // keyMap defines a set of keybindings. To work for help it must satisfy
 // key.Map. It could also very easily be a map[string]key.Binding.
 type keyMap struct {
-	Delete  key.Binding
-	Refresh key.Binding
-	Move    key.Binding
-	Update  key.Binding
-	Install key.Binding
-	Select  key.Binding
-	Cancel  key.Binding
+	Delete     key.Binding
+	Refresh    key.Binding
+	Left       key.Binding
+	Right      key.Binding
+	Update     key.Binding
+	Install    key.Binding
+	Add        key.Binding
+	ShowValues key.Binding
+	Template   key.Binding
+	Edit       key.Binding
+	Select     key.Binding
+	Preview    key.Binding
+	Next       key.Binding
+	Cancel     key.Binding
 }
 
 // ShortHelp returns keybindings to be shown in the mini help view. It's part
 // of the key.Map interface.
 func (k keyMap) ShortHelp() []key.Binding {
-	return []key.Binding{k.Delete, k.Update, k.Move, k.Select, k.Refresh, k.Install, k.Cancel}
+	return []key.Binding{k.Delete, k.Update, k.Left, k.Right, k.Select, k.Refresh, k.Add, k.Install, k.ShowValues, k.Template, k.Edit, k.Preview, k.Next, k.Cancel}
 }
 
 // FullHelp returns keybindings for the expanded help view. It's part of the
@@ -32,15 +40,21 @@ var defaultValuesKeyHelp = keyMap{
 	Cancel: key.NewBinding(key.WithKeys("esc"), key.WithHelp("esc", "Cancel")),
 }
 
+var templateKeys = keyMap{
+	Edit:   key.NewBinding(key.WithKeys("v"), key.WithHelp("v", "Edit values")),
+	Cancel: key.NewBinding(key.WithKeys("esc"), key.WithHelp("esc", "Close")),
+}
+
 var repoListKeys = keyMap{
 	Delete: key.NewBinding(
 		key.WithKeys("D"),
 		key.WithHelp("D", "Delete repo"),
 	),
-	Move:    key.NewBinding(key.WithKeys("h", "	j", "k", "l", "up", "down"), key.WithHelp("hjkl/←↑↓→", "Move")),
+	Right:   key.NewBinding(key.WithKeys("l", "right"), key.WithHelp("l/→", "Charts")),
 	Refresh: key.NewBinding(key.WithKeys("r"), key.WithHelp("r", "Refresh")),
 	Select:  key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "Select")),
 	Update:  key.NewBinding(key.WithKeys("u"), key.WithHelp("u", "Update repo")),
+	Add:     key.NewBinding(key.WithKeys("a"), key.WithHelp("a", "Add repo")),
 	Install: key.NewBinding(key.WithKeys("i"), key.WithHelp("i", "Install version")),
 }
 
@@ -49,9 +63,12 @@ var chartsListKeys = keyMap{
 		key.WithKeys("D"),
 		key.WithHelp("D", "Delete repo"),
 	),
-	Move:    key.NewBinding(key.WithKeys("h", "	j", "k", "l", "left", "right", "up", "down"), key.WithHelp("hjkl/←/↑/↓/→", "Move")),
+	Left:    key.NewBinding(key.WithKeys("h", "left"), key.WithHelp("h/←", "Repositories")),
+	Right:   key.NewBinding(key.WithKeys("l", "right"), key.WithHelp("l/→", "Versions")),
+	Refresh: key.NewBinding(key.WithKeys("r"), key.WithHelp("r", "Refresh")),
 	Select:  key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "Select")),
 	Update:  key.NewBinding(key.WithKeys("u"), key.WithHelp("u", "Update repo")),
+	Add:     key.NewBinding(key.WithKeys("a"), key.WithHelp("a", "Add repo")),
 	Install: key.NewBinding(key.WithKeys("i"), key.WithHelp("i", "Install version")),
 }
 
@@ -60,9 +77,38 @@ var versionsKeys = keyMap{
 		key.WithKeys("D"),
 		key.WithHelp("D", "Delete repo"),
 	),
-	Move:    key.NewBinding(key.WithKeys("h", "	j", "k", "l", "left", "right", "up", "down"), key.WithHelp("hjkl/←↑↓→", "Move")),
-	Update:  key.NewBinding(key.WithKeys("u"), key.WithHelp("u", "Upgrade repo")),
-	Install: key.NewBinding(key.WithKeys("i"), key.WithHelp("i", "Install version")),
+	Left:       key.NewBinding(key.WithKeys("h", "left"), key.WithHelp("h/←", "Charts")),
+	Refresh:    key.NewBinding(key.WithKeys("r"), key.WithHelp("r", "Refresh")),
+	Update:     key.NewBinding(key.WithKeys("u"), key.WithHelp("u", "Upgrade repo")),
+	Add:        key.NewBinding(key.WithKeys("a"), key.WithHelp("a", "Add repo")),
+	Install:    key.NewBinding(key.WithKeys("i"), key.WithHelp("i", "Install version")),
+	ShowValues: key.NewBinding(key.WithKeys("v"), key.WithHelp("v", "Default values")),
+	Template:   key.NewBinding(key.WithKeys("t"), key.WithHelp("t", "Render templates")),
+}
+
+func init() {
+	for _, keys := range []*keyMap{&repoListKeys, &chartsListKeys, &versionsKeys} {
+		helpers.RegisterKeys("repositories", map[string]*key.Binding{
+			"delete": &keys.Delete, "update": &keys.Update, "add": &keys.Add, "install": &keys.Install, "refresh": &keys.Refresh,
+		}, "global", "table")
+	}
+	helpers.RegisterKeys("repositories", map[string]*key.Binding{
+		"showValues": &versionsKeys.ShowValues, "template": &versionsKeys.Template,
+		"select": &repoListKeys.Select, "next": &repoListKeys.Right, "previous": &chartsListKeys.Left,
+	})
+	helpers.RegisterKeys("repositories", map[string]*key.Binding{
+		"select": &chartsListKeys.Select, "next": &chartsListKeys.Right, "previous": &versionsKeys.Left,
+		"back": &defaultValuesKeyHelp.Cancel,
+	})
+	helpers.RegisterKeys("repositories.template", map[string]*key.Binding{"edit": &templateKeys.Edit, "close": &templateKeys.Cancel}, "global", "manifest")
+}
+
+// withoutChanges hides the bindings refused in read-only mode.
+func (k keyMap) withoutChanges() keyMap {
+	k.Install.SetEnabled(false)
+	k.Add.SetEnabled(false)
+	k.Delete.SetEnabled(false)
+	return k
 }
 
 func generateKeys() []keyMap {
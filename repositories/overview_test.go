@@ -1 +1,56 @@
 package repositories
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/pidanou/helm-tui/helm"
+	"github.com/pidanou/helm-tui/helpers"
+	"github.com/pidanou/helm-tui/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestTemplate verifies that t renders the templates of the selected version
+// with its default values, then with the edited ones, without installing.
+func TestTemplate(t *testing.T) {
+	helpers.UserDir = t.TempDir()
+	client := helm.NewFakeClient()
+	client.Templates["bitnami/nginx"] = "kind: Deployment\nmetadata:\n  name: nginx\n---\nkind: Service\nmetadata:\n  name: nginx\n"
+	model, _ := InitModel(client)
+	model, _ = model.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	model, _ = model.Update(types.PackagesMsg{Content: []table.Row{{"bitnami/nginx"}}})
+	model, _ = model.Update(types.PackageVersionsMsg{Content: []table.Row{{"1.0.0", "1.27", "NGINX"}}})
+
+	right := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("l")}
+	model, _ = model.Update(right)
+	model, _ = model.Update(right)
+
+	model, cmd := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("t")})
+	model, _ = model.Update(cmd())
+	m := model.(Model)
+	require.True(t, m.templating)
+	assert.False(t, m.template.edited)
+	assert.Contains(t, m.template.content, "━━ Deployment (1)")
+	assert.Contains(t, m.template.content, "━━ Service (1)")
+	assert.Len(t, m.template.sections, 2)
+	assert.Contains(t, client.Calls, "Template nginx bitnami/nginx 1.0.0 default")
+
+	file := templateValuesFile("bitnami/nginx")
+	require.NoError(t, os.MkdirAll(filepath.Dir(file), 0755))
+	require.NoError(t, os.WriteFile(file, []byte("replicaCount: 3\n"), 0644))
+	model, cmd = model.Update(types.EditorFinishedMsg{})
+	model, _ = model.Update(cmd())
+	m = model.(Model)
+	assert.True(t, m.template.edited)
+	assert.Contains(t, client.Calls, "Template nginx bitnami/nginx 1.0.0 default "+file)
+
+	model, cmd = model.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	cmd()
+	assert.False(t, model.(Model).templating)
+	assert.NoFileExists(t, file)
+	assert.NotContains(t, client.Calls, "Install nginx bitnami/nginx 1.0.0 default")
+}
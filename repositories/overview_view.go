@@ -3,12 +3,17 @@ package repositories
 import (
 	"github.com/charmbracelet/bubbles/table"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/pidanou/helm-tui/components"
 	"github.com/pidanou/helm-tui/helpers"
 	"github.com/pidanou/helm-tui/styles"
 )
 
 func (m Model) View() string {
-	helpView := m.help.View(m.keys[m.selectedView])
+	keys := m.keys[m.selectedView]
+	if m.readOnly() {
+		keys = keys.withoutChanges()
+	}
+	helpView := m.help.View(keys) + m.help.Styles.ShortSeparator.Render(" • ") + m.help.View(components.SortKeys)
 	repoView := m.renderTable(m.tables[listView], " Repositories ", m.selectedView == listView)
 	packagesView := m.renderTable(m.tables[packagesView], " Packages ", m.selectedView == packagesView)
 	versionsView := m.renderTable(m.tables[versionsView], " Versions ", m.selectedView == versionsView)
@@ -19,6 +24,9 @@ func (m Model) View() string {
 	if m.adding {
 		return m.addModel.View()
 	}
+	if m.templating {
+		return m.renderTemplateView()
+	}
 	if m.showDefaultValue {
 		return m.renderDefaultValueView()
 	}
@@ -34,7 +42,7 @@ func (m Model) renderTable(table table.Model, title string, active bool) string
 	baseStyle = styles.InactiveStyle.Border(styles.Border, false, true, true)
 	topBorder = styles.GenerateTopBorderWithTitle(title, table.Width(), styles.Border, styles.InactiveStyle)
 	if active {
-		topBorder = styles.GenerateTopBorderWithTitle(title, table.Width(), styles.Border, styles.ActiveStyle.Foreground(styles.HighlightColor))
+		topBorder = styles.GenerateTopBorderWithTitle(title, table.Width(), styles.Border, styles.ActiveStyle.Foreground(styles.CurrentTheme.Highlight))
 		baseStyle = styles.ActiveStyle.Border(styles.Border, false, true, true)
 	}
 	tableView = baseStyle.Render(tableView)
@@ -0,0 +1,120 @@
+package repositories
+
+import (
+	"fmt"
+	"os"
+	"path"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/pidanou/helm-tui/components"
+	"github.com/pidanou/helm-tui/config"
+	"github.com/pidanou/helm-tui/helm"
+	"github.com/pidanou/helm-tui/helpers"
+	"github.com/pidanou/helm-tui/styles"
+	"github.com/pidanou/helm-tui/types"
+)
+
+// templatesDir holds the values edited to render the templates of a chart.
+// Namespace names cannot start with a dot, so it never holds the values of
+// a wizard.
+const templatesDir = ".templates"
+
+// templateMsg holds the manifest rendered from the templates of a chart.
+type templateMsg struct {
+	chart, version string
+	// edited is set when the values were edited instead of the defaults.
+	edited  bool
+	content string
+	// sections are the lines of content the resource kinds start at.
+	sections []int
+	err      error
+}
+
+// templateValuesFile is where the values of chart are edited.
+func templateValuesFile(chart string) string {
+	return path.Join(helpers.UserDir, templatesDir, chart, "values.yaml")
+}
+
+// renderTemplate renders the templates of a chart version like helm template,
+// with the edited values or the default ones.
+func (m Model) renderTemplate(chart, version string, edited bool) tea.Cmd {
+	return func() tea.Msg {
+		opts := helm.InstallOptions{ReleaseName: path.Base(chart), Chart: chart, Version: version, Namespace: config.Current.Namespace()}
+		if edited {
+			opts.ValuesFile = templateValuesFile(chart)
+		}
+		manifest, err := m.client.Template(opts)
+		if err != nil {
+			return templateMsg{chart: chart, version: version, edited: edited, err: err}
+		}
+		content, sections := components.RenderManifest(manifest, "")
+		return templateMsg{chart: chart, version: version, edited: edited, content: content, sections: sections}
+	}
+}
+
+// editTemplateValues edits the values the templates are rendered with,
+// starting from the default values of the chart.
+func (m Model) editTemplateValues() tea.Cmd {
+	file := templateValuesFile(m.template.chart)
+	if _, err := os.Stat(file); err == nil {
+		return helpers.OpenFile(file)
+	}
+	_ = os.MkdirAll(path.Dir(file), 0755)
+	values, err := m.client.ShowValues(m.template.chart, m.template.version)
+	if err != nil {
+		return func() tea.Msg { return types.EditorFinishedMsg{Err: err} }
+	}
+	return helpers.WriteAndOpenFile([]byte(values), file)
+}
+
+func (m Model) cleanTemplateValues() tea.Msg {
+	_ = os.RemoveAll(path.Join(helpers.UserDir, templatesDir))
+	return nil
+}
+
+// updateTemplate handles the key presses while the templates are shown.
+func (m Model) updateTemplate(msg tea.KeyMsg) (Model, tea.Cmd) {
+	var cmd tea.Cmd
+	switch {
+	case key.Matches(msg, templateKeys.Edit):
+		return m, m.editTemplateValues()
+	case key.Matches(msg, components.ManifestKeys.Next):
+		components.JumpTo(&m.templateVP, m.template.sections, true)
+	case key.Matches(msg, components.ManifestKeys.Previous):
+		components.JumpTo(&m.templateVP, m.template.sections, false)
+	case key.Matches(msg, templateKeys.Cancel):
+		m.templating = false
+		return m, m.cleanTemplateValues
+	default:
+		m.templateVP, cmd = m.templateVP.Update(msg)
+	}
+	return m, cmd
+}
+
+// showTemplate opens the template view on the content of msg.
+func (m *Model) showTemplate(msg templateMsg) {
+	m.templating = true
+	m.template = msg
+	m.templateVP = viewport.New(m.width-2, m.height-3) // borders and help
+	content := msg.content
+	if msg.err != nil {
+		content = msg.err.Error()
+	}
+	m.templateVP.SetContent(content)
+}
+
+func (m Model) renderTemplateView() string {
+	values := "default values"
+	if m.template.edited {
+		values = "edited values"
+	}
+	title := fmt.Sprintf(" Templates of %s %s, %s ", m.template.chart, m.template.version, values)
+	topBorder := styles.GenerateTopBorderWithTitle(title, m.templateVP.Width, styles.Border, styles.ActiveStyle.Foreground(styles.CurrentTheme.Highlight))
+	baseStyle := styles.ActiveStyle.Border(styles.Border, false, true, true)
+	helperStyle := m.help.Styles.ShortSeparator
+	helpView := m.help.View(components.ManifestKeys) + helperStyle.Render(" • ") + m.help.View(templateKeys) + helperStyle.Render(" • ") + m.help.View(helpers.CommonKeys)
+	return lipgloss.JoinVertical(lipgloss.Top, topBorder, baseStyle.Render(m.templateVP.View()), helpView)
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/pidanou/helm-tui/helm"
+	"github.com/pidanou/helm-tui/styles"
+	"github.com/pidanou/helm-tui/types"
+)
+
+// statusTimeout is how long an error stays in the status bar.
+const statusTimeout = 10 * time.Second
+
+type clearStatusMsg struct {
+	tag int
+}
+
+// statusError returns the error carried by the result of a command. Release
+// details are left out as their errors are shown in place, and so are
+// namespaces, which are only used for suggestions.
+func statusError(msg tea.Msg) error {
+	switch msg := msg.(type) {
+	case types.DeleteMsg:
+		return msg.Err
+	case types.ReadOnlyMsg:
+		return msg.Err
+	case types.ListReleasesMsg:
+		return msg.Err
+	case types.RollbackMsg:
+		return msg.Err
+	case types.UpgradeMsg:
+		return msg.Err
+	case types.InstallMsg:
+		return msg.Err
+	case types.EditorFinishedMsg:
+		return msg.Err
+	case types.RemoveMsg:
+		return msg.Err
+	case types.ListRepoMsg:
+		return msg.Err
+	case types.PackagesMsg:
+		return msg.Err
+	case types.PackageVersionsMsg:
+		return msg.Err
+	case types.AddRepoMsg:
+		return msg.Err
+	case types.UpdateRepoMsg:
+		return msg.Err
+	case types.DefaultValueMsg:
+		return msg.Err
+	case types.HubSearchResultMsg:
+		return msg.Err
+	case types.HubSearchDefaultValueMsg:
+		return msg.Err
+	case types.PluginsListMsg:
+		return msg.Err
+	case types.PluginInstallMsg:
+		return msg.Err
+	case types.PluginUpdateMsg:
+		return msg.Err
+	case types.PluginUninstallMsg:
+		return msg.Err
+	case types.KubeContextsMsg:
+		return msg.Err
+	}
+	return nil
+}
+
+func (m mainModel) setError(err error) (mainModel, tea.Cmd) {
+	m.err = err
+	m.errorVP.SetContent(errorDetails(err))
+	m.errorVP.GotoTop()
+	m.statusTag++
+	tag := m.statusTag
+	return m, tea.Tick(statusTimeout, func(_ time.Time) tea.Msg {
+		return clearStatusMsg{tag: tag}
+	})
+}
+
+// errorSummary describes an error on a single line.
+func errorSummary(err error) string {
+	message, _, _ := strings.Cut(strings.TrimSpace(err.Error()), "\n")
+	var cmdErr *helm.CommandError
+	if errors.As(err, &cmdErr) {
+		return fmt.Sprintf("%s (exit %d): %s", cmdErr.Command(), cmdErr.ExitCode, message)
+	}
+	return message
+}
+
+func errorDetails(err error) string {
+	var cmdErr *helm.CommandError
+	if errors.As(err, &cmdErr) {
+		return fmt.Sprintf("Command:   %s\nExit code: %d\n\n%s", cmdErr.Command(), cmdErr.ExitCode, strings.TrimSpace(err.Error()))
+	}
+	return err.Error()
+}
+
+func (m mainModel) renderStatusBar() string {
+	if m.err == nil {
+		return ""
+	}
+	hint := styles.InactiveStyle.Faint(true).Render(" • " + globalKeys.Errors.Help().Key + " details")
+	status := lipgloss.NewStyle().Foreground(styles.CurrentTheme.Error).MaxWidth(max(0, m.width-lipgloss.Width(hint))).Render("✗ " + errorSummary(m.err))
+	return status + hint
+}
+
+func (m mainModel) renderErrorDetails() string {
+	view := styles.InactiveStyle.Padding(1, 2).Border(styles.Border, false, true, true).BorderForeground(styles.CurrentTheme.Error).Render(m.errorVP.View())
+	topBorder := styles.GenerateTopBorderWithTitle(" Error ", m.errorVP.Width+4, styles.Border, lipgloss.NewStyle().Foreground(styles.CurrentTheme.Error))
+	helpView := m.help.View(errorKeys)
+	return lipgloss.JoinVertical(lipgloss.Left, topBorder, view, helpView)
+}
+
+type errorKeyMap struct {
+	Scroll key.Binding
+	Close  key.Binding
+}
+
+var errorKeys = errorKeyMap{
+	Scroll: key.NewBinding(key.WithKeys("up", "down", "k", "j"), key.WithHelp("↑↓/kj", "Scroll")),
+	Close:  key.NewBinding(key.WithKeys("esc", "e"), key.WithHelp("esc/e", "Close")),
+}
+
+func (k errorKeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{k.Scroll, k.Close}
+}
+
+// FullHelp returns keybindings for the expanded help view. It's part of the
+// key.Map interface.
+func (k errorKeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{}
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"errors"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/pidanou/helm-tui/config"
+	"github.com/pidanou/helm-tui/helm"
+	"github.com/pidanou/helm-tui/types"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestStatusBarShowsCommandError verifies that a failed command is summarized in the status bar and can be expanded.
+func TestStatusBarShowsCommandError(t *testing.T) {
+	m := newModel(tabLabels, helm.NewFakeClient(), startOptions{})
+	updated, _ := m.Update(tea.WindowSizeMsg{Width: 200, Height: 40})
+	err := &helm.CommandError{
+		Args:     []string{"helm", "uninstall", "web", "--namespace", "default"},
+		ExitCode: 1,
+		Stderr:   "Error: uninstall: Release not loaded: web: release: not found\n",
+		Err:      errors.New("exit status 1"),
+	}
+
+	updated, cmd := updated.Update(types.DeleteMsg{Err: err})
+	m = updated.(mainModel)
+
+	assert.NotNil(t, cmd)
+	assert.Contains(t, m.renderStatusBar(), "helm uninstall web --namespace default (exit 1): Error: uninstall: Release not loaded")
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("e")})
+	m = updated.(mainModel)
+	assert.True(t, m.showError, "e should expand the error")
+	assert.Contains(t, m.errorVP.View(), "Exit code: 1")
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	m = updated.(mainModel)
+	assert.False(t, m.showError)
+	assert.Nil(t, m.err, "Closing the details should dismiss the error")
+}
+
+// TestStatusBarClears verifies that only the latest error's timer clears the status bar.
+func TestStatusBarClears(t *testing.T) {
+	m := newModel(tabLabels, helm.NewFakeClient(), startOptions{})
+	updated, _ := m.Update(types.PluginInstallMsg{Err: errors.New("first")})
+	updated, _ = updated.Update(types.PluginInstallMsg{Err: errors.New("second")})
+
+	updated, _ = updated.Update(clearStatusMsg{tag: 1})
+	assert.EqualError(t, updated.(mainModel).err, "second")
+
+	updated, _ = updated.Update(clearStatusMsg{tag: 2})
+	assert.Nil(t, updated.(mainModel).err)
+}
+
+// TestReadOnlyBadge verifies that the tab header flags read-only kube contexts.
+func TestReadOnlyBadge(t *testing.T) {
+	config.Current.ReadOnlyContexts = []string{"production"}
+	t.Cleanup(func() { config.Current = config.Default() })
+	client := helm.NewFakeClient()
+	client.Context = "staging"
+	m := newModel(tabLabels, client, startOptions{})
+	assert.NotContains(t, m.renderMenu(), "READ-ONLY")
+
+	updated, _ := m.Update(types.SwitchKubeContextMsg{Context: "production"})
+
+	assert.Contains(t, updated.(mainModel).renderMenu(), "READ-ONLY")
+}
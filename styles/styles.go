@@ -20,7 +20,6 @@ var (
 	ActiveTabStyle    = InactiveTabStyle.Border(ActiveTabBorder, true)
 	WindowSize        tea.WindowSizeMsg
 	Border            = lipgloss.Border(lipgloss.RoundedBorder())
-	HighlightColor    = lipgloss.AdaptiveColor{Light: "#874BFD", Dark: "#7D56F4"}
 	InactiveStyle     = lipgloss.NewStyle()
-	ActiveStyle       = InactiveStyle.BorderForeground(HighlightColor)
+	ActiveStyle       = InactiveStyle.BorderForeground(CurrentTheme.Highlight)
 )
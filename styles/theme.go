@@ -0,0 +1,121 @@
+package styles
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Theme holds the colors every view is rendered with.
+type Theme struct {
+	// Highlight colors the focused borders, the titles and the current tab.
+	Highlight lipgloss.TerminalColor
+	// Error colors the status bar and the error details.
+	Error lipgloss.TerminalColor
+	// Subtle colors separators such as the table header border.
+	Subtle lipgloss.TerminalColor
+	// Changed colors the rows that changed since the previous refresh.
+	Changed lipgloss.TerminalColor
+	// Success and Pending color the deployed releases and those waiting for
+	// an operation to complete, failed ones taking the Error color.
+	Success lipgloss.TerminalColor
+	Pending lipgloss.TerminalColor
+	// SelectedForeground and SelectedBackground color the selected table
+	// row.
+	SelectedForeground lipgloss.TerminalColor
+	SelectedBackground lipgloss.TerminalColor
+	// Reverse renders the selected row and the current tab in reverse
+	// video, for themes without colors.
+	Reverse bool
+}
+
+// Themes are the themes selectable with the theme option.
+var Themes = map[string]Theme{
+	"dark": {
+		Highlight:          lipgloss.Color("#7D56F4"),
+		Error:              lipgloss.Color("#FF5F5F"),
+		Subtle:             lipgloss.Color("240"),
+		Changed:            lipgloss.Color("#FFAF00"),
+		Success:            lipgloss.Color("#5FD75F"),
+		Pending:            lipgloss.Color("#5FAFFF"),
+		SelectedForeground: lipgloss.Color("229"),
+		SelectedBackground: lipgloss.Color("57"),
+	},
+	"light": {
+		Highlight:          lipgloss.Color("#874BFD"),
+		Error:              lipgloss.Color("#D70000"),
+		Subtle:             lipgloss.Color("250"),
+		Changed:            lipgloss.Color("#AF5F00"),
+		Success:            lipgloss.Color("#008700"),
+		Pending:            lipgloss.Color("#005FAF"),
+		SelectedForeground: lipgloss.Color("#1C1C1C"),
+		SelectedBackground: lipgloss.Color("#D7D7FF"),
+	},
+	"high-contrast": {
+		Highlight:          lipgloss.Color("11"),
+		Error:              lipgloss.Color("9"),
+		Subtle:             lipgloss.Color("15"),
+		Changed:            lipgloss.Color("14"),
+		Success:            lipgloss.Color("10"),
+		Pending:            lipgloss.Color("12"),
+		SelectedForeground: lipgloss.Color("0"),
+		SelectedBackground: lipgloss.Color("11"),
+	},
+	"monochrome": {
+		Highlight:          lipgloss.NoColor{},
+		Error:              lipgloss.NoColor{},
+		Subtle:             lipgloss.NoColor{},
+		Changed:            lipgloss.NoColor{},
+		Success:            lipgloss.NoColor{},
+		Pending:            lipgloss.NoColor{},
+		SelectedForeground: lipgloss.NoColor{},
+		SelectedBackground: lipgloss.NoColor{},
+		Reverse:            true,
+	},
+}
+
+// ThemeNames returns the names of Themes, sorted.
+func ThemeNames() []string {
+	names := make([]string, 0, len(Themes))
+	for name := range Themes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// CurrentTheme is the theme in use.
+var CurrentTheme = Themes["dark"]
+
+// SetTheme switches to the named theme. An empty name picks dark or light
+// from the terminal background. NO_COLOR, when set, always selects
+// monochrome.
+func SetTheme(name string) error {
+	if name == "" {
+		name = "light"
+		if lipgloss.HasDarkBackground() {
+			name = "dark"
+		}
+	}
+	theme, ok := Themes[name]
+	if !ok {
+		return fmt.Errorf("unknown theme %q, expected one of %s", name, strings.Join(ThemeNames(), ", "))
+	}
+	if os.Getenv("NO_COLOR") != "" {
+		theme = Themes["monochrome"]
+	}
+	CurrentTheme = theme
+	ActiveStyle = InactiveStyle.BorderForeground(theme.Highlight)
+	return nil
+}
+
+// Selected returns style marked as the current selection of the theme.
+func Selected(style lipgloss.Style) lipgloss.Style {
+	if CurrentTheme.Reverse {
+		return style.Reverse(true)
+	}
+	return style.Foreground(CurrentTheme.SelectedForeground).Background(CurrentTheme.SelectedBackground)
+}
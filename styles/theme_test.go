@@ -0,0 +1,27 @@
+package styles
+
+import (
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestSetTheme verifies that the named theme is applied and that NO_COLOR forces monochrome.
+func TestSetTheme(t *testing.T) {
+	t.Cleanup(func() { CurrentTheme = Themes["dark"] })
+	t.Setenv("NO_COLOR", "")
+
+	assert.NoError(t, SetTheme("light"))
+	assert.Equal(t, Themes["light"], CurrentTheme)
+	assert.Equal(t, Themes["light"].Highlight, ActiveStyle.GetBorderTopForeground())
+
+	assert.EqualError(t, SetTheme("solarized"), `unknown theme "solarized", expected one of dark, high-contrast, light, monochrome`)
+	assert.Equal(t, Themes["light"], CurrentTheme, "An unknown theme should keep the current one")
+
+	t.Setenv("NO_COLOR", "1")
+	assert.NoError(t, SetTheme("dark"))
+	assert.True(t, CurrentTheme.Reverse)
+	assert.Equal(t, lipgloss.NoColor{}, CurrentTheme.Highlight)
+	assert.True(t, Selected(lipgloss.NewStyle()).GetReverse())
+}
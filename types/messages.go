@@ -77,6 +77,8 @@ type InstallMsg struct {
 
 type EditorFinishedMsg struct {
 	Err error
+	// Invalid is set when the edited file is not valid YAML.
+	Invalid error
 }
 
 type AddRepoMsg struct {
@@ -122,3 +124,26 @@ type PluginUpdateMsg struct {
 type PluginUninstallMsg struct {
 	Err error
 }
+
+type KubeContextsMsg struct {
+	Content []table.Row
+	Current string
+	Err     error
+}
+
+// ReadOnlyMsg reports an action refused in read-only mode.
+type ReadOnlyMsg struct {
+	Err error
+}
+
+// CloseKubeContextsMsg closes the kube context switcher without switching.
+type CloseKubeContextsMsg struct{}
+
+type SwitchKubeContextMsg struct {
+	Context string
+}
+
+type NamespacesMsg struct {
+	Content []string
+	Err     error
+}
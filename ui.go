@@ -5,9 +5,16 @@ import (
 	"path"
 	"strings"
 
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/pidanou/helm-tui/activity"
+	"github.com/pidanou/helm-tui/config"
+	"github.com/pidanou/helm-tui/contexts"
+	"github.com/pidanou/helm-tui/helm"
 	"github.com/pidanou/helm-tui/helpers"
 	"github.com/pidanou/helm-tui/hub"
 	"github.com/pidanou/helm-tui/plugins"
@@ -19,36 +26,69 @@ import (
 
 type tabIndex uint
 
-var tabLabels = []string{"Releases", "Repositories", "Hub", "Plugins"}
+const activityFile = "activity.jsonl"
+
+var tabLabels = []string{"Releases", "Repositories", "Hub", "Plugins", "Activity"}
 
 const (
 	releasesTab tabIndex = iota
 	repositoriesTab
 	hubTab
 	pluginsTab
+	activityTab
 )
 
+// inputFocuser is implemented by tabs that can capture text input, during
+// which global shortcuts must not fire.
+type inputFocuser interface {
+	InputFocused() bool
+}
+
 type mainModel struct {
-	state      tabIndex
-	index      int
-	width      int
-	height     int
-	tabs       []string
-	tabContent []tea.Model
-	loaded     bool
+	state        tabIndex
+	index        int
+	width        int
+	height       int
+	tabs         []string
+	tabContent   []tea.Model
+	contexts     contexts.Model
+	showContexts bool
+	kubeContext  string
+	err          error
+	showError    bool
+	statusTag    int
+	errorVP      viewport.Model
+	help         help.Model
+	activity     *helm.ActivityLog
+	// persistActivity appends the activity log to activityFile in the
+	// working directory.
+	persistActivity bool
+	loaded          bool
+}
+
+// startOptions are the command line flags shaping the first screen.
+type startOptions struct {
+	// namespace scopes the releases instead of the remembered namespaces.
+	namespace string
+	// release, as namespace/name, is opened in the details view.
+	release string
 }
 
-func newModel(tabs []string) mainModel {
-	m := mainModel{state: releasesTab, tabs: tabs, tabContent: make([]tea.Model, len(tabs)), loaded: false}
-	m.tabContent[releasesTab], _ = releases.InitModel()
-	m.tabContent[repositoriesTab], _ = repositories.InitModel()
-	m.tabContent[hubTab] = hub.InitModel()
-	m.tabContent[pluginsTab] = plugins.InitModel()
+func newModel(tabs []string, client helm.HelmClient, opts startOptions) mainModel {
+	m := mainModel{state: tabIndex(max(0, config.Current.TabIndex())), tabs: tabs, tabContent: make([]tea.Model, len(tabs)), help: help.New(), activity: client.Activity(), loaded: false}
+	m.kubeContext = client.KubeContext()
+	m.contexts = contexts.InitModel(client)
+	releasesModel, _ := releases.InitModel(client)
+	m.tabContent[releasesTab] = releasesModel.StartIn(opts.namespace, opts.release)
+	m.tabContent[repositoriesTab], _ = repositories.InitModel(client)
+	m.tabContent[hubTab] = hub.InitModel(client)
+	m.tabContent[pluginsTab] = plugins.InitModel(client)
+	m.tabContent[activityTab] = activity.InitModel(client)
 	return m
 }
 
 func (m mainModel) Init() tea.Cmd {
-	var cmds = []tea.Cmd{createWorkingDir, textinput.Blink}
+	var cmds = []tea.Cmd{createWorkingDir, textinput.Blink, m.contexts.Init()}
 	for _, i := range m.tabContent {
 		cmds = append(cmds, i.Init())
 	}
@@ -58,12 +98,40 @@ func (m mainModel) Init() tea.Cmd {
 func (m mainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmd tea.Cmd
 	var cmds []tea.Cmd
+	if err := statusError(msg); err != nil {
+		m, cmd = m.setError(err)
+		cmds = append(cmds, cmd)
+	}
 	switch msg := msg.(type) {
+	case clearStatusMsg:
+		if msg.tag == m.statusTag && !m.showError {
+			m.err = nil
+		}
+		return m, nil
 	case types.InitAppMsg:
 		if msg.Err != nil {
 			return m, tea.Quit
 		}
 		m.loaded = true
+		if m.persistActivity {
+			err := m.activity.Persist(path.Join(helpers.UserDir, activityFile))
+			if err != nil {
+				m, cmd = m.setError(err)
+				cmds = append(cmds, cmd)
+			}
+		}
+	case types.KubeContextsMsg:
+		if msg.Err == nil {
+			m.kubeContext = msg.Current
+		}
+		m.contexts, cmd = m.contexts.Update(msg)
+		cmds = append(cmds, cmd)
+		return m, tea.Batch(cmds...)
+	case types.CloseKubeContextsMsg:
+		m.showContexts = false
+	case types.SwitchKubeContextMsg:
+		m.showContexts = false
+		m.kubeContext = msg.Context
 	case types.EditorFinishedMsg:
 		switch m.state {
 		case releasesTab:
@@ -78,24 +146,59 @@ func (m mainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		m.height = msg.Height
-		m.tabContent[releasesTab], cmd = m.tabContent[releasesTab].Update(tea.WindowSizeMsg{Width: m.width, Height: msg.Height - lipgloss.Height(m.renderMenu())})
-		m.tabContent[repositoriesTab], cmd = m.tabContent[repositoriesTab].Update(tea.WindowSizeMsg{Width: m.width, Height: msg.Height - lipgloss.Height(m.renderMenu())})
-		m.tabContent[hubTab], cmd = m.tabContent[hubTab].Update(tea.WindowSizeMsg{Width: m.width, Height: msg.Height - lipgloss.Height(m.renderMenu())})
-		m.tabContent[pluginsTab], cmd = m.tabContent[pluginsTab].Update(tea.WindowSizeMsg{Width: m.width, Height: msg.Height - lipgloss.Height(m.renderMenu())})
+		m.tabContent[releasesTab], cmd = m.tabContent[releasesTab].Update(tea.WindowSizeMsg{Width: m.width, Height: m.contentHeight()})
+		m.tabContent[repositoriesTab], cmd = m.tabContent[repositoriesTab].Update(tea.WindowSizeMsg{Width: m.width, Height: m.contentHeight()})
+		m.tabContent[hubTab], cmd = m.tabContent[hubTab].Update(tea.WindowSizeMsg{Width: m.width, Height: m.contentHeight()})
+		m.tabContent[pluginsTab], cmd = m.tabContent[pluginsTab].Update(tea.WindowSizeMsg{Width: m.width, Height: m.contentHeight()})
+		m.tabContent[activityTab], cmd = m.tabContent[activityTab].Update(tea.WindowSizeMsg{Width: m.width, Height: m.contentHeight()})
+		m.contexts, _ = m.contexts.Update(tea.WindowSizeMsg{Width: m.width, Height: m.contentHeight()})
+		m.errorVP = viewport.New(m.width-6, m.contentHeight()-5) // borders, padding and help
+		if m.err != nil {
+			m.errorVP.SetContent(errorDetails(m.err))
+		}
 		return m, tea.Batch(cmds...)
 	case tea.KeyMsg:
-		switch msg.String() {
-		case "ctrl+c":
+		if m.showError {
+			switch {
+			case key.Matches(msg, helpers.CommonKeys.Quit):
+				return m, tea.Quit
+			case key.Matches(msg, errorKeys.Close):
+				m.showError = false
+				m.err = nil
+				return m, nil
+			}
+			m.errorVP, cmd = m.errorVP.Update(msg)
+			return m, cmd
+		}
+		if m.showContexts {
+			if key.Matches(msg, helpers.CommonKeys.Quit) {
+				return m, tea.Quit
+			}
+			m.contexts, cmd = m.contexts.Update(msg)
+			return m, cmd
+		}
+		switch {
+		case key.Matches(msg, helpers.CommonKeys.Quit):
 			return m, tea.Quit
-		case "]":
-			if m.state == pluginsTab {
+		case key.Matches(msg, globalKeys.Contexts):
+			if !m.inputFocused() {
+				m.showContexts = true
+				return m, m.contexts.Init()
+			}
+		case key.Matches(msg, globalKeys.Errors):
+			if m.err != nil && !m.inputFocused() {
+				m.showError = true
+				return m, nil
+			}
+		case key.Matches(msg, helpers.CommonKeys.NextTab):
+			if m.state == activityTab {
 				m.state = 0
 			} else {
 				m.state++
 			}
-		case "[":
+		case key.Matches(msg, helpers.CommonKeys.PrevTab):
 			if m.state == releasesTab {
-				m.state = pluginsTab
+				m.state = activityTab
 			} else {
 				m.state--
 			}
@@ -117,6 +220,10 @@ func (m mainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.tabContent[pluginsTab], cmd = m.tabContent[pluginsTab].Update(msg)
 			cmds = append(cmds, cmd)
 			return m, tea.Batch(cmds...)
+		case activityTab:
+			m.tabContent[activityTab], cmd = m.tabContent[activityTab].Update(msg)
+			cmds = append(cmds, cmd)
+			return m, tea.Batch(cmds...)
 		}
 	}
 	m.tabContent[releasesTab], cmd = m.tabContent[releasesTab].Update(msg)
@@ -127,6 +234,8 @@ func (m mainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	cmds = append(cmds, cmd)
 	m.tabContent[pluginsTab], cmd = m.tabContent[pluginsTab].Update(msg)
 	cmds = append(cmds, cmd)
+	m.tabContent[activityTab], cmd = m.tabContent[activityTab].Update(msg)
+	cmds = append(cmds, cmd)
 	return m, tea.Batch(cmds...)
 }
 
@@ -137,10 +246,32 @@ func (m mainModel) View() string {
 	}
 	doc.WriteString(m.renderMenu())
 	doc.WriteString("\n")
-	doc.WriteString(m.tabContent[m.state].View())
+	var content string
+	switch {
+	case m.showError:
+		content = m.renderErrorDetails()
+	case m.showContexts:
+		content = m.contexts.View()
+	default:
+		content = m.tabContent[m.state].View()
+	}
+	doc.WriteString(lipgloss.NewStyle().Height(m.contentHeight()).Render(content))
+	doc.WriteString("\n")
+	doc.WriteString(m.renderStatusBar())
 	return doc.String()
 }
 
+// contentHeight is the height left to the tabs by the menu and the status
+// bar.
+func (m mainModel) contentHeight() int {
+	return m.height - lipgloss.Height(m.renderMenu()) - 1
+}
+
+func (m mainModel) inputFocused() bool {
+	tab, ok := m.tabContent[m.state].(inputFocuser)
+	return ok && tab.InputFocused()
+}
+
 func (m mainModel) renderMenu() string {
 	doc := strings.Builder{}
 
@@ -150,23 +281,33 @@ func (m mainModel) renderMenu() string {
 		var style lipgloss.Style
 		isActive := i == int(m.state)
 		if isActive {
-			style = styles.ActiveStyle.Background(styles.HighlightColor).Padding(0, 1)
+			style = styles.Selected(styles.ActiveStyle).Padding(0, 1)
 		} else {
 			style = styles.InactiveStyle.Padding(0, 1)
 		}
 		renderedTabs = append(renderedTabs, style.Render(t))
 	}
 	menu := lipgloss.JoinHorizontal(lipgloss.Top, renderedTabs...)
+	var status string
+	if config.Current.ReadOnlyIn(m.kubeContext) {
+		status = lipgloss.NewStyle().Bold(true).Reverse(true).Foreground(styles.CurrentTheme.Error).Padding(0, 1).Render("READ-ONLY")
+	}
+	if m.kubeContext != "" {
+		status += styles.InactiveStyle.Padding(0, 1).Render("⎈ " + m.kubeContext)
+	}
+	if status != "" {
+		gap := strings.Repeat(" ", max(0, m.width-lipgloss.Width(menu)-lipgloss.Width(status)))
+		menu = lipgloss.JoinHorizontal(lipgloss.Top, menu, gap, status)
+	}
 	doc.WriteString(menu)
 	return doc.String()
 }
 
 func createWorkingDir() tea.Msg {
-	homeDir, err := os.UserHomeDir()
+	workingDir, err := config.Dir()
 	if err != nil {
 		return types.InitAppMsg{Err: err}
 	}
-	workingDir := path.Join(homeDir, ".helm-tui")
 	err = os.MkdirAll(workingDir, 0755)
 	if err != nil {
 		return types.InitAppMsg{Err: err}